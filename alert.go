@@ -0,0 +1,101 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Handles requests to alert endpoint
+// Use NewAlertService instead of direct creation AlertService
+type AlertService struct {
+	client *Client
+	url    string
+}
+
+// NewAlertService creates AlertService with defined url
+func NewAlertService(client *Client) *AlertService {
+	alertService := AlertService{}
+	alertService.client = client
+	alertService.url = "alerts"
+	return &alertService
+}
+
+type PaginatedAlertsResponse struct {
+	PaginatedResponse
+	Alerts []*Alert `json:"results"`
+}
+
+type Alert struct {
+	ID           string `json:"id"`
+	AlertGroupId string `json:"alert_group_id"`
+	CreatedAt    string `json:"created_at"`
+	// Payload is the raw alert as it was received by integration
+	Payload json.RawMessage `json:"payload"`
+}
+
+type ListAlertOptions struct {
+	ListOptions
+	AlertGroupId  string `url:"alert_group_id,omitempty" json:"alert_group_id,omitempty"`
+	IntegrationId string `url:"integration_id,omitempty" json:"integration_id,omitempty"`
+	Search        string `url:"search,omitempty" json:"search,omitempty"`
+}
+
+// ListAlerts gets alerts for authorized team
+func (service *AlertService) ListAlerts(opt *ListAlertOptions, options ...RequestOption) (*PaginatedAlertsResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var alerts *PaginatedAlertsResponse
+	resp, err := service.client.Do(req, &alerts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return alerts, resp, err
+}
+
+// AlertIterator iterates over alerts of all pages.
+// Use AlertService.Iter to create one.
+type AlertIterator struct {
+	pager
+	items []*Alert
+}
+
+// Iter returns iterator over all alerts matching opt, following pagination links
+func (service *AlertService) Iter(opt *ListAlertOptions, options ...RequestOption) *AlertIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &AlertIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedAlertsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Alerts
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current alert
+func (it *AlertIterator) Value() *Alert {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllAlerts gets alerts of all pages
+func (service *AlertService) ListAllAlerts(opt *ListAlertOptions, options ...RequestOption) ([]*Alert, error) {
+	var alerts []*Alert
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		alerts = append(alerts, it.Value())
+	}
+	return alerts, it.Err()
+}
@@ -0,0 +1,212 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Handles requests to alert group (incident) endpoint
+// Use NewAlertGroupService instead of direct creation AlertGroupService
+type AlertGroupService struct {
+	client *Client
+	url    string
+}
+
+// NewAlertGroupService creates AlertGroupService with defined url
+func NewAlertGroupService(client *Client) *AlertGroupService {
+	alertGroupService := AlertGroupService{}
+	alertGroupService.client = client
+	alertGroupService.url = "alert_groups"
+	return &alertGroupService
+}
+
+// Alert group states
+const (
+	AlertGroupStateNew          = "new"
+	AlertGroupStateAcknowledged = "acknowledged"
+	AlertGroupStateResolved     = "resolved"
+	AlertGroupStateSilenced     = "silenced"
+)
+
+type PaginatedAlertGroupsResponse struct {
+	PaginatedResponse
+	AlertGroups []*AlertGroup `json:"results"`
+}
+
+type AlertGroup struct {
+	ID             string  `json:"id"`
+	IntegrationId  string  `json:"integration_id"`
+	RouteId        string  `json:"route_id"`
+	AlertsCount    int     `json:"alerts_count"`
+	State          string  `json:"state"`
+	Title          *string `json:"title"`
+	CreatedAt      string  `json:"created_at"`
+	AcknowledgedAt *string `json:"acknowledged_at"`
+	AcknowledgedBy *string `json:"acknowledged_by"`
+	ResolvedAt     *string `json:"resolved_at"`
+	ResolvedBy     *string `json:"resolved_by"`
+	SilencedAt     *string `json:"silenced_at"`
+}
+
+type ListAlertGroupOptions struct {
+	ListOptions
+	IntegrationId string `url:"integration_id,omitempty" json:"integration_id,omitempty"`
+	RouteId       string `url:"route_id,omitempty" json:"route_id,omitempty"`
+	State         string `url:"state,omitempty" json:"state,omitempty"`
+	// StartedAt filters alert groups created in time range, use AlertGroupTimeRange to build it
+	StartedAt string `url:"started_at,omitempty" json:"started_at,omitempty"`
+}
+
+// AlertGroupTimeRange formats time range for ListAlertGroupOptions.StartedAt
+func AlertGroupTimeRange(from, to time.Time) string {
+	const layout = "2006-01-02T15:04:05"
+	return fmt.Sprintf("%s_%s", from.UTC().Format(layout), to.UTC().Format(layout))
+}
+
+// ListAlertGroups gets alert groups for authorized team
+func (service *AlertGroupService) ListAlertGroups(opt *ListAlertGroupOptions, options ...RequestOption) (*PaginatedAlertGroupsResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var alertGroups *PaginatedAlertGroupsResponse
+	resp, err := service.client.Do(req, &alertGroups)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return alertGroups, resp, err
+}
+
+// AlertGroupIterator iterates over alert groups of all pages.
+// Use AlertGroupService.Iter to create one.
+type AlertGroupIterator struct {
+	pager
+	items []*AlertGroup
+}
+
+// Iter returns iterator over all alert groups matching opt, following pagination links
+func (service *AlertGroupService) Iter(opt *ListAlertGroupOptions, options ...RequestOption) *AlertGroupIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &AlertGroupIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedAlertGroupsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.AlertGroups
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current alertGroup
+func (it *AlertGroupIterator) Value() *AlertGroup {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllAlertGroups gets alert groups of all pages
+func (service *AlertGroupService) ListAllAlertGroups(opt *ListAlertGroupOptions, options ...RequestOption) ([]*AlertGroup, error) {
+	var alertGroups []*AlertGroup
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		alertGroups = append(alertGroups, it.Value())
+	}
+	return alertGroups, it.Err()
+}
+
+type GetAlertGroupOptions struct {
+}
+
+// Get alert group by given id
+func (service *AlertGroupService) GetAlertGroup(id string, opt *GetAlertGroupOptions, options ...RequestOption) (*AlertGroup, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alertGroup := new(AlertGroup)
+	resp, err := service.client.Do(req, alertGroup)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return alertGroup, resp, err
+}
+
+// Acknowledges alert group
+func (service *AlertGroupService) AcknowledgeAlertGroup(id string, options ...RequestOption) (*http.Response, error) {
+	return service.action(id, "acknowledge", nil, options)
+}
+
+// Removes acknowledgement from alert group
+func (service *AlertGroupService) UnacknowledgeAlertGroup(id string, options ...RequestOption) (*http.Response, error) {
+	return service.action(id, "unacknowledge", nil, options)
+}
+
+// Resolves alert group
+func (service *AlertGroupService) ResolveAlertGroup(id string, options ...RequestOption) (*http.Response, error) {
+	return service.action(id, "resolve", nil, options)
+}
+
+// Reopens resolved alert group
+func (service *AlertGroupService) UnresolveAlertGroup(id string, options ...RequestOption) (*http.Response, error) {
+	return service.action(id, "unresolve", nil, options)
+}
+
+type SilenceAlertGroupOptions struct {
+	// Delay is silence duration in seconds, -1 silences forever
+	Delay int `json:"delay"`
+}
+
+// Silences alert group for given delay
+func (service *AlertGroupService) SilenceAlertGroup(id string, opt *SilenceAlertGroupOptions, options ...RequestOption) (*http.Response, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("silence options required")
+	}
+	return service.action(id, "silence", opt, options)
+}
+
+// Removes silence from alert group
+func (service *AlertGroupService) UnsilenceAlertGroup(id string, options ...RequestOption) (*http.Response, error) {
+	return service.action(id, "unsilence", nil, options)
+}
+
+func (service *AlertGroupService) action(id, action string, opt interface{}, options []RequestOption) (*http.Response, error) {
+	u := fmt.Sprintf("%s/%s/%s/", service.url, id, action)
+
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
+
+type DeleteAlertGroupOptions struct {
+}
+
+// Deletes alert group
+func (service *AlertGroupService) DeleteAlertGroup(id string, opt *DeleteAlertGroupOptions, options ...RequestOption) (*http.Response, error) {
+
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
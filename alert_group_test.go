@@ -0,0 +1,176 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testAlertGroupTitle = "Memory above 90%"
+var testAlertGroupAcknowledgedAt = "2020-05-19T13:37:01.429805Z"
+var testAlertGroupAcknowledgedBy = "U4DNY931HHJS5"
+
+var testAlertGroup = &AlertGroup{
+	ID:             "I68T24C13IFW1",
+	IntegrationId:  "CFRPV98RPR1U8",
+	RouteId:        "RIYGUJXCPFHXY",
+	AlertsCount:    3,
+	State:          AlertGroupStateAcknowledged,
+	Title:          &testAlertGroupTitle,
+	CreatedAt:      "2020-05-19T12:37:01.430444Z",
+	AcknowledgedAt: &testAlertGroupAcknowledgedAt,
+	AcknowledgedBy: &testAlertGroupAcknowledgedBy,
+}
+
+var testAlertGroupBody = `{
+	"id": "I68T24C13IFW1",
+	"integration_id": "CFRPV98RPR1U8",
+	"route_id": "RIYGUJXCPFHXY",
+	"alerts_count": 3,
+	"state": "acknowledged",
+	"title": "Memory above 90%",
+	"created_at": "2020-05-19T12:37:01.430444Z",
+	"acknowledged_at": "2020-05-19T13:37:01.429805Z",
+	"acknowledged_by": "U4DNY931HHJS5",
+	"resolved_at": null,
+	"resolved_by": null,
+	"silenced_at": null
+}`
+
+func TestListAlertGroups(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	from := time.Date(2020, 5, 19, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mux.HandleFunc("/api/v1/alert_groups/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		q := r.URL.Query()
+		if q.Get("integration_id") != "CFRPV98RPR1U8" || q.Get("state") != "acknowledged" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if got, want := q.Get("started_at"), "2020-05-19T00:00:00_2020-05-20T00:00:00"; got != want {
+			t.Errorf("started_at is %q, want %q", got, want)
+		}
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testAlertGroupBody))
+	})
+
+	options := &ListAlertGroupOptions{
+		IntegrationId: "CFRPV98RPR1U8",
+		State:         AlertGroupStateAcknowledged,
+		StartedAt:     AlertGroupTimeRange(from, to),
+	}
+
+	alertGroups, _, err := client.AlertGroups.ListAlertGroups(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PaginatedAlertGroupsResponse{
+		PaginatedResponse: PaginatedResponse{
+			Count:    1,
+			Next:     nil,
+			Previous: nil,
+		},
+		AlertGroups: []*AlertGroup{
+			testAlertGroup,
+		},
+	}
+	if !reflect.DeepEqual(want, alertGroups) {
+		t.Errorf("returned\n %+v, \nwant\n %+v", alertGroups, want)
+	}
+}
+
+func TestGetAlertGroup(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/alert_groups/I68T24C13IFW1/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testAlertGroupBody)
+	})
+
+	alertGroup, _, err := client.AlertGroups.GetAlertGroup("I68T24C13IFW1", &GetAlertGroupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testAlertGroup
+
+	if !reflect.DeepEqual(want, alertGroup) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", alertGroup, want)
+	}
+}
+
+func TestAlertGroupActions(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	var called []string
+	for _, action := range []string{"acknowledge", "unacknowledge", "resolve", "unresolve", "unsilence"} {
+		action := action
+		mux.HandleFunc("/api/v1/alert_groups/I68T24C13IFW1/"+action+"/", func(w http.ResponseWriter, r *http.Request) {
+			testRequestMethod(t, r, "POST")
+			called = append(called, action)
+		})
+	}
+
+	actions := []func(string, ...RequestOption) (*http.Response, error){
+		client.AlertGroups.AcknowledgeAlertGroup,
+		client.AlertGroups.UnacknowledgeAlertGroup,
+		client.AlertGroups.ResolveAlertGroup,
+		client.AlertGroups.UnresolveAlertGroup,
+		client.AlertGroups.UnsilenceAlertGroup,
+	}
+	for _, action := range actions {
+		if _, err := action("I68T24C13IFW1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"acknowledge", "unacknowledge", "resolve", "unresolve", "unsilence"}
+	if !reflect.DeepEqual(want, called) {
+		t.Errorf("called %v, want %v", called, want)
+	}
+}
+
+func TestSilenceAlertGroup(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/alert_groups/I68T24C13IFW1/silence/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		var body map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["delay"] != 1800 {
+			t.Errorf("delay is %d, want 1800", body["delay"])
+		}
+	})
+
+	if _, err := client.AlertGroups.SilenceAlertGroup("I68T24C13IFW1", &SilenceAlertGroupOptions{Delay: 1800}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AlertGroups.SilenceAlertGroup("I68T24C13IFW1", nil); err == nil {
+		t.Error("expected error without silence options")
+	}
+}
+
+func TestDeleteAlertGroup(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/alert_groups/I68T24C13IFW1/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "DELETE")
+	})
+
+	_, err := client.AlertGroups.DeleteAlertGroup("I68T24C13IFW1", &DeleteAlertGroupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,71 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testAlert = &Alert{
+	ID:           "AA74DN7T4JQB6",
+	AlertGroupId: "I68T24C13IFW1",
+	CreatedAt:    "2020-05-11T20:07:43Z",
+	Payload:      json.RawMessage(`{"state": "alerting", "title": "[Alerting] Test notification", "evalMatches": [{"value": 100, "metric": "High value"}]}`),
+}
+
+var testAlertBody = `{
+	"id": "AA74DN7T4JQB6",
+	"alert_group_id": "I68T24C13IFW1",
+	"created_at": "2020-05-11T20:07:43Z",
+	"payload": {"state": "alerting", "title": "[Alerting] Test notification", "evalMatches": [{"value": 100, "metric": "High value"}]}
+}`
+
+func TestListAlerts(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/alerts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		q := r.URL.Query()
+		if q.Get("alert_group_id") != "I68T24C13IFW1" || q.Get("search") != "High value" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testAlertBody))
+	})
+
+	options := &ListAlertOptions{
+		AlertGroupId: "I68T24C13IFW1",
+		Search:       "High value",
+	}
+
+	alerts, _, err := client.Alerts.ListAlerts(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PaginatedAlertsResponse{
+		PaginatedResponse: PaginatedResponse{
+			Count:    1,
+			Next:     nil,
+			Previous: nil,
+		},
+		Alerts: []*Alert{
+			testAlert,
+		},
+	}
+	if !reflect.DeepEqual(want, alerts) {
+		t.Errorf("returned\n %+v, \nwant\n %+v", alerts, want)
+	}
+
+	var payload struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(alerts.Alerts[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.State != "alerting" {
+		t.Errorf("payload state is %q, want %q", payload.State, "alerting")
+	}
+}
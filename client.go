@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -16,13 +15,15 @@ import (
 	"time"
 
 	"github.com/google/go-querystring/query"
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 	"golang.org/x/time/rate"
 )
 
 const (
-	defaultBaseURL = "https://amixr.io/"
-	apiVersionPath = "api/v1/"
+	defaultBaseURL   = "https://amixr.io/"
+	apiVersionPath   = "api/v1/"
+	defaultUserAgent = "amixr-go-client"
 )
 
 type ListOptions struct {
@@ -40,21 +41,34 @@ type Client struct {
 	client         *retryablehttp.Client
 	token          string
 	baseURL        *url.URL
+	userAgent      string
 	disableRetries bool
+	timeout        time.Duration
 	limiter        *rate.Limiter
+	logger         Logger
 	// List of Services. Keep in sync with func newClient
-	Integrations  *IntegrationService
-	Escalations   *EscalationService
-	Users         *UserService
-	Schedules     *ScheduleService
-	Routes        *RouteService
-	SlackChannels *SlackChannelService
-	UserGroups    *UserGroupService
-	CustomActions *CustomActionService
-	OnCallShifts  *OnCallShiftService
+	Integrations              *IntegrationService
+	Escalations               *EscalationService
+	Users                     *UserService
+	Schedules                 *ScheduleService
+	Routes                    *RouteService
+	SlackChannels             *SlackChannelService
+	UserGroups                *UserGroupService
+	CustomActions             *CustomActionService
+	OnCallShifts              *OnCallShiftService
+	AlertGroups               *AlertGroupService
+	Alerts                    *AlertService
+	PersonalNotificationRules *PersonalNotificationRuleService
+	EscalationChains          *EscalationChainService
+	IntegrationHeartbeats     *IntegrationHeartbeatService
+	Organization              *OrganizationService
+	Config                    *ConfigService
 }
 
-func NewClient(token string) (*Client, error) {
+// NewClient returns a new API client authorized with given token.
+// Defaults can be overridden with ClientOption, e.g. WithBaseURL
+// to talk to a self-hosted instance.
+func NewClient(token string, opts ...ClientOption) (*Client, error) {
 	if token == "" {
 		return nil, fmt.Errorf("Token required")
 	}
@@ -63,19 +77,39 @@ func NewClient(token string) (*Client, error) {
 		return nil, err
 	}
 	client.token = token
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	if client.timeout > 0 {
+		// the given HTTP client may be shared, e.g. http.DefaultClient
+		httpClient := *client.client.HTTPClient
+		httpClient.Timeout = client.timeout
+		client.client.HTTPClient = &httpClient
+	}
 	return client, nil
 }
 
 func newClient() (*Client, error) {
-	c := &Client{}
+	c := &Client{userAgent: defaultUserAgent, logger: noopLogger{}}
 
 	// Configure the HTTP client.
 	c.client = &retryablehttp.Client{
+		HTTPClient:   cleanhttp.DefaultPooledClient(),
 		Backoff:      c.retryHTTPBackoff,
 		CheckRetry:   c.retryHTTPCheck,
 		RetryWaitMin: 100 * time.Millisecond,
 		RetryWaitMax: 400 * time.Millisecond,
 		RetryMax:     5,
+		// Let CheckResponse inspect the last response once retries are exhausted.
+		ErrorHandler:   retryablehttp.PassthroughErrorHandler,
+		RequestLogHook: c.logRequestAttempt,
+		Logger:         c.logger,
 	}
 	// https://docs.amixr.io/#/rate-limits
 	baseLimit := 50.0 / 60
@@ -95,6 +129,13 @@ func newClient() (*Client, error) {
 	c.UserGroups = NewUserGroupService(c)
 	c.CustomActions = NewCustomActionService(c)
 	c.OnCallShifts = NewOnCallShiftService(c)
+	c.AlertGroups = NewAlertGroupService(c)
+	c.Alerts = NewAlertService(c)
+	c.PersonalNotificationRules = NewPersonalNotificationRuleService(c)
+	c.EscalationChains = NewEscalationChainService(c)
+	c.IntegrationHeartbeats = NewIntegrationHeartbeatService(c)
+	c.Organization = NewOrganizationService(c)
+	c.Config = NewConfigService(c)
 
 	return c, nil
 }
@@ -110,9 +151,14 @@ func (c *Client) setBaseURL(urlStr string) error {
 	return nil
 }
 
-func (c *Client) NewRequest(method, path string, opt interface{}) (*retryablehttp.Request, error) {
+// NewRequest creates an API request. Path is relative to the base URL of the client.
+// opt is encoded as JSON body for POST and PUT requests and as query string otherwise.
+func (c *Client) NewRequest(method, path string, opt interface{}, options ...RequestOption) (*retryablehttp.Request, error) {
 	u := *c.baseURL
 	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set the encoded path data
 	u.RawPath = c.baseURL.Path + path
@@ -122,6 +168,9 @@ func (c *Client) NewRequest(method, path string, opt interface{}) (*retryablehtt
 	reqHeaders := make(http.Header)
 	reqHeaders.Set("Accept", "application/json")
 	reqHeaders.Set("Authorization", c.token)
+	if c.userAgent != "" {
+		reqHeaders.Set("User-Agent", c.userAgent)
+	}
 
 	var body interface{}
 	switch {
@@ -143,12 +192,24 @@ func (c *Client) NewRequest(method, path string, opt interface{}) (*retryablehtt
 	}
 
 	req, err := retryablehttp.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set the request specific headers.
 	for k, v := range reqHeaders {
 		req.Header[k] = v
 	}
 
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
@@ -158,15 +219,18 @@ func (c *Client) NewRequest(method, path string, opt interface{}) (*retryablehtt
 func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*http.Response, error) {
 	err := c.limiter.Wait(req.Context())
 	if err != nil {
-		log.Println("limiter")
+		c.logger.Warn("rate limiter wait failed", "method", req.Method, "path", req.URL.Path, "error", err)
 		return nil, err
 	}
 
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
+		c.logger.Error("request failed", "method", req.Method, "path", req.URL.Path, "latency", time.Since(start), "error", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.logger.Debug("request completed", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "latency", time.Since(start))
 
 	err = CheckResponse(resp)
 	if err != nil {
@@ -186,6 +250,11 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*http.Response,
 	return resp, err
 }
 
+// CheckResponse checks the API response for errors and returns them if present.
+// Returned error is always an *ErrorResponse or wraps one:
+// *ValidationError for rejected input and *ServerError for 5xx responses.
+// Use errors.Is with ErrUnauthorized, ErrForbidden, ErrNotFound and ErrRateLimited
+// to check for particular status.
 func CheckResponse(r *http.Response) error {
 	switch r.StatusCode {
 	case 200, 201, 202, 204, 304:
@@ -194,20 +263,27 @@ func CheckResponse(r *http.Response) error {
 
 	errorResponse := &ErrorResponse{Response: r}
 	data, err := ioutil.ReadAll(r.Body)
-	if err == nil && data != nil {
-		errorResponse.Body = data
-
-		// Very naive realization if handling errors messages
-		var rawError interface{}
-		if err := json.Unmarshal(data, &rawError); err != nil {
-			errorResponse.Message = "failed to parse unknown error format"
-		} else {
-			errorResponse.Message = parseError(rawError)
-		}
-	}
 	if err != nil {
 		return err
 	}
+	errorResponse.Body = data
+
+	var rawError interface{}
+	if err := json.Unmarshal(data, &rawError); err != nil {
+		errorResponse.Message = "failed to parse unknown error format"
+		rawError = nil
+	} else {
+		errorResponse.Message = parseError(rawError)
+	}
+
+	switch {
+	case r.StatusCode == http.StatusBadRequest || r.StatusCode == http.StatusUnprocessableEntity:
+		if fields := parseFieldErrors(rawError); fields != nil {
+			return &ValidationError{ErrorResponse: errorResponse, Fields: fields}
+		}
+	case r.StatusCode >= 500:
+		return &ServerError{ErrorResponse: errorResponse}
+	}
 
 	return errorResponse
 }
@@ -264,7 +340,9 @@ func (c *Client) retryHTTPCheck(ctx context.Context, resp *http.Response, err er
 
 func (c *Client) retryHTTPBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
 	if resp != nil && resp.StatusCode == 429 {
-		return rateLimitBackoff(min, max, attemptNum, resp)
+		wait := rateLimitBackoff(min, max, attemptNum, resp)
+		c.logger.Info("rate limited", "attempt", attemptNum, "wait", wait)
+		return wait
 	}
 
 	return retryablehttp.LinearJitterBackoff(min, max, attemptNum, resp)
@@ -273,11 +351,9 @@ func (c *Client) retryHTTPBackoff(min, max time.Duration, attemptNum int, resp *
 func rateLimitBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 	jitter := time.Duration(rnd.Float64() * float64(max-min))
-	log.Printf("[DEBUG] ratelimited call")
 	if resp != nil {
 		if v := resp.Header.Get("RateLimit-Reset"); v != "" {
 			if reset, _ := strconv.ParseInt(v, 10, 64); reset > 0 {
-				log.Printf("[DEBUG] reset in '%d", reset)
 				min = time.Duration(reset) * time.Second
 			}
 		}
@@ -286,6 +362,10 @@ func rateLimitBackoff(min, max time.Duration, attemptNum int, resp *http.Respons
 	return min + jitter
 }
 
+func (c *Client) logRequestAttempt(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	c.logger.Debug("sending request", "method", req.Method, "path", req.URL.Path, "attempt", attempt)
+}
+
 func (c *Client) BaseURL() *url.URL {
 	u := *c.baseURL
 	return &u
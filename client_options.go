@@ -0,0 +1,123 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ClientOption can be used to customize a new Client
+// e.g. amixr.NewClient(token, amixr.WithBaseURL("https://amixr.example.com/"))
+type ClientOption func(*Client) error
+
+// WithBaseURL sets the URL of the amixr instance the client talks to.
+// Only scheme, host and optional path prefix should be given, API version path is appended.
+func WithBaseURL(urlStr string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			return fmt.Errorf("invalid base URL %q: %w", urlStr, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid base URL %q: scheme must be http or https", urlStr)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: host required", urlStr)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		u.RawPath = ""
+		u.RawQuery = ""
+		u.Fragment = ""
+		return c.setBaseURL(u.String() + apiVersionPath)
+	}
+}
+
+// WithHTTPClient sets the underlying http.Client used to perform requests.
+// Retries and rate limiting are still handled by Client.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("HTTP client required")
+		}
+		c.client.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithRetryPolicy sets maximum number of retries and wait bounds between them.
+// Zero retryMax disables retries.
+func WithRetryPolicy(retryMax int, waitMin, waitMax time.Duration) ClientOption {
+	return func(c *Client) error {
+		if retryMax < 0 {
+			return fmt.Errorf("retry max must not be negative, got %d", retryMax)
+		}
+		if waitMin < 0 || waitMax < 0 {
+			return fmt.Errorf("retry wait must not be negative")
+		}
+		if waitMin > waitMax {
+			return fmt.Errorf("retry wait min %s is greater than max %s", waitMin, waitMax)
+		}
+		c.client.RetryMax = retryMax
+		c.client.RetryWaitMin = waitMin
+		c.client.RetryWaitMax = waitMax
+		return nil
+	}
+}
+
+// WithRateLimit replaces default client side rate limit of 50 requests per minute.
+// Use rate.Inf to disable limiting.
+func WithRateLimit(limit rate.Limit, burst int) ClientOption {
+	return func(c *Client) error {
+		if limit <= 0 {
+			return fmt.Errorf("rate limit must be positive, got %v", limit)
+		}
+		if burst <= 0 && limit != rate.Inf {
+			return fmt.Errorf("rate limit burst must be positive, got %d", burst)
+		}
+		c.limiter = rate.NewLimiter(limit, burst)
+		return nil
+	}
+}
+
+// WithUserAgent sets User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return fmt.Errorf("user agent must not be empty")
+		}
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout sets timeout of a single HTTP attempt.
+// It is set on a copy of the HTTP client, so the client given to WithHTTPClient isn't modified
+// and the order of options doesn't matter.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithLogger sets logger for request and retry events. By default client does not log.
+// Use NewStdLogger to log with standard library logger.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("logger required")
+		}
+		c.logger = logger
+		c.client.Logger = logger
+		return nil
+	}
+}
@@ -0,0 +1,153 @@
+package amixr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestWithBaseURL(t *testing.T) {
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"https://amixr.example.com", "https://amixr.example.com/api/v1/"},
+		{"https://amixr.example.com/", "https://amixr.example.com/api/v1/"},
+		{"http://localhost:8080/oncall", "http://localhost:8080/oncall/api/v1/"},
+		{"http://localhost:8080/oncall/?debug=1", "http://localhost:8080/oncall/api/v1/"},
+	}
+
+	for _, tt := range tests {
+		c, err := NewClient("token", WithBaseURL(tt.base))
+		if err != nil {
+			t.Fatalf("NewClient(%q) returned error: %v", tt.base, err)
+		}
+		if got := c.BaseURL().String(); got != tt.want {
+			t.Errorf("BaseURL for %q is %s, want %s", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestClientOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  ClientOption
+	}{
+		{"base url without scheme", WithBaseURL("amixr.example.com")},
+		{"base url with unsupported scheme", WithBaseURL("ftp://amixr.example.com")},
+		{"base url without host", WithBaseURL("https://")},
+		{"nil http client", WithHTTPClient(nil)},
+		{"negative retry max", WithRetryPolicy(-1, 0, 0)},
+		{"negative retry wait", WithRetryPolicy(1, -time.Second, time.Second)},
+		{"retry wait min above max", WithRetryPolicy(1, 2*time.Second, time.Second)},
+		{"zero rate limit", WithRateLimit(0, 1)},
+		{"zero burst", WithRateLimit(1, 0)},
+		{"empty user agent", WithUserAgent(" ")},
+		{"zero timeout", WithTimeout(0)},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewClient("token", tt.opt); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	mux, server, _ := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "deploy-bot/1.0" {
+			t.Errorf("User-Agent is %q, want %q", got, "deploy-bot/1.0")
+		}
+		if got := r.Header.Get("Authorization"); got != "token" {
+			t.Errorf("Authorization is %q, want %q", got, "token")
+		}
+		w.Write([]byte(`{"count": 0, "next": null, "previous": null, "results": []}`))
+	})
+
+	client, err := NewClient("token", WithBaseURL(server.URL), WithUserAgent("deploy-bot/1.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Users.ListUsers(&ListUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithHTTPClientAndTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	transport := &http.Transport{}
+	httpClient := &http.Client{Transport: transport}
+	// timeout applies regardless of the order of options
+	client, err := NewClient("token",
+		WithBaseURL(server.URL),
+		WithTimeout(20*time.Millisecond),
+		WithHTTPClient(httpClient),
+		WithRetryPolicy(0, 0, 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.client.HTTPClient.Transport != transport {
+		t.Fatal("expected given HTTP client to be used")
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("given HTTP client was modified, timeout %s", httpClient.Timeout)
+	}
+
+	if _, err := client.Schedules.DeleteSchedule("SBM7DV7BKFUYU", &DeleteScheduleOptions{}); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(2, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Schedules.DeleteSchedule("SBM7DV7BKFUYU", &DeleteScheduleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("server called %d times, want 3", got)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	client, err := NewClient("token", WithRateLimit(rate.Limit(10), 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.limiter.Limit() != rate.Limit(10) || client.limiter.Burst() != 5 {
+		t.Errorf("limiter is %v/%d, want 10/5", client.limiter.Limit(), client.limiter.Burst())
+	}
+
+	if _, err := NewClient("token", WithRateLimit(rate.Inf, 0)); err != nil {
+		t.Errorf("unlimited rate returned error: %v", err)
+	}
+}
@@ -14,8 +14,7 @@ func setup(t *testing.T) (*http.ServeMux, *httptest.Server, *Client) {
 
 	server := httptest.NewServer(mux)
 
-	client, err := NewClient("token")
-	err = client.setBaseURL(server.URL + "/api/v1/")
+	client, err := NewClient("token", WithBaseURL(server.URL))
 	if err != nil {
 		server.Close()
 		t.Fatalf("Failed to create client: %v", err)
@@ -0,0 +1,245 @@
+package amixr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Handles declarative configuration of integrations and schedules
+// Use NewConfigService instead of direct creation ConfigService
+type ConfigService struct {
+	client *Client
+}
+
+// NewConfigService creates ConfigService
+func NewConfigService(client *Client) *ConfigService {
+	configService := ConfigService{}
+	configService.client = client
+	return &configService
+}
+
+// Config is declarative configuration of integrations with their routes and escalations,
+// schedules with their on-call shifts, escalation chains with their escalations and custom actions.
+// Optional fields left empty aren't managed, their live values are kept.
+type Config struct {
+	Integrations     []*IntegrationConfig     `json:"integrations,omitempty" yaml:"integrations,omitempty"`
+	Schedules        []*ScheduleConfig        `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	EscalationChains []*EscalationChainConfig `json:"escalation_chains,omitempty" yaml:"escalation_chains,omitempty"`
+	// UserGroups and SlackChannels are exported for reference, they aren't reconciled
+	UserGroups    []*UserGroupConfig    `json:"user_groups,omitempty" yaml:"user_groups,omitempty"`
+	CustomActions []*CustomActionConfig `json:"custom_actions,omitempty" yaml:"custom_actions,omitempty"`
+	SlackChannels []string              `json:"slack_channels,omitempty" yaml:"slack_channels,omitempty"`
+}
+
+// IntegrationConfig is integration identified by its name
+type IntegrationConfig struct {
+	Name      string           `json:"name" yaml:"name"`
+	Type      string           `json:"type" yaml:"type"`
+	Templates *TemplatesConfig `json:"templates,omitempty" yaml:"templates,omitempty"`
+	// DefaultRoute is the route created with integration, which matches alerts not matched by Routes.
+	// It isn't managed if nil.
+	DefaultRoute *RouteConfig `json:"default_route,omitempty" yaml:"default_route,omitempty"`
+	// Routes are in the order they are evaluated
+	Routes []*RouteConfig `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// TemplatesConfig are integration templates, nil templates aren't managed
+type TemplatesConfig struct {
+	GroupingKey   *string              `json:"grouping_key,omitempty" yaml:"grouping_key,omitempty"`
+	ResolveSignal *string              `json:"resolve_signal,omitempty" yaml:"resolve_signal,omitempty"`
+	Slack         *SlackTemplateConfig `json:"slack,omitempty" yaml:"slack,omitempty"`
+}
+
+type SlackTemplateConfig struct {
+	Title    *string `json:"title,omitempty" yaml:"title,omitempty"`
+	Message  *string `json:"message,omitempty" yaml:"message,omitempty"`
+	ImageURL *string `json:"image_url,omitempty" yaml:"image_url,omitempty"`
+}
+
+// RouteConfig is route identified by its routing regex within integration
+type RouteConfig struct {
+	// RoutingRegex must be empty for the default route
+	RoutingRegex string `json:"routing_regex,omitempty" yaml:"routing_regex,omitempty"`
+	// EscalationChain is name of an escalation chain of the config or id of another chain
+	EscalationChain string `json:"escalation_chain,omitempty" yaml:"escalation_chain,omitempty"`
+	SlackChannel    string `json:"slack_channel,omitempty" yaml:"slack_channel,omitempty"`
+	// Escalations are identified by their position within route
+	Escalations []*EscalationConfig `json:"escalations,omitempty" yaml:"escalations,omitempty"`
+}
+
+// EscalationChainConfig is escalation chain identified by its name
+type EscalationChainConfig struct {
+	Name string `json:"name" yaml:"name"`
+	// Escalations are identified by their position within chain
+	Escalations []*EscalationConfig `json:"escalations,omitempty" yaml:"escalations,omitempty"`
+}
+
+// EscalationConfig is escalation step. All its fields are managed.
+type EscalationConfig struct {
+	Type string `json:"type" yaml:"type"`
+	// Duration of wait step in seconds
+	Duration                    int      `json:"duration,omitempty" yaml:"duration,omitempty"`
+	PersonsToNotify             []string `json:"persons_to_notify,omitempty" yaml:"persons_to_notify,omitempty"`
+	PersonsToNotifyNextEachTime []string `json:"persons_to_notify_next_each_time,omitempty" yaml:"persons_to_notify_next_each_time,omitempty"`
+	// Schedule is name of a schedule of the config or id of another schedule
+	Schedule         string `json:"notify_on_call_from_schedule,omitempty" yaml:"notify_on_call_from_schedule,omitempty"`
+	GroupToNotify    string `json:"group_to_notify,omitempty" yaml:"group_to_notify,omitempty"`
+	ActionToTrigger  string `json:"action_to_trigger,omitempty" yaml:"action_to_trigger,omitempty"`
+	Important        bool   `json:"important,omitempty" yaml:"important,omitempty"`
+	NotifyIfTimeFrom string `json:"notify_if_time_from,omitempty" yaml:"notify_if_time_from,omitempty"`
+	NotifyIfTimeTo   string `json:"notify_if_time_to,omitempty" yaml:"notify_if_time_to,omitempty"`
+}
+
+// ScheduleConfig is schedule identified by its name. Schedule with iCal URL is ical schedule without shifts.
+type ScheduleConfig struct {
+	Name         string               `json:"name" yaml:"name"`
+	TimeZone     string               `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
+	ICalUrl      string               `json:"ical_url,omitempty" yaml:"ical_url,omitempty"`
+	SlackChannel string               `json:"slack_channel,omitempty" yaml:"slack_channel,omitempty"`
+	Shifts       []*OnCallShiftConfig `json:"shifts,omitempty" yaml:"shifts,omitempty"`
+}
+
+// OnCallShiftConfig is on-call shift identified by its name within schedule.
+// Type, level, start, duration and users are always managed.
+type OnCallShiftConfig struct {
+	Name  string `json:"name" yaml:"name"`
+	Type  string `json:"type" yaml:"type"`
+	Level int    `json:"level,omitempty" yaml:"level,omitempty"`
+	// Start is wall time in the schedule time zone like 2020-09-04T09:00:00
+	Start string `json:"start" yaml:"start"`
+	// Duration in seconds
+	Duration     int        `json:"duration" yaml:"duration"`
+	Frequency    string     `json:"frequency,omitempty" yaml:"frequency,omitempty"`
+	Interval     int        `json:"interval,omitempty" yaml:"interval,omitempty"`
+	WeekStart    string     `json:"week_start,omitempty" yaml:"week_start,omitempty"`
+	ByDay        []string   `json:"by_day,omitempty" yaml:"by_day,omitempty"`
+	ByMonth      []int      `json:"by_month,omitempty" yaml:"by_month,omitempty"`
+	ByMonthday   []int      `json:"by_monthday,omitempty" yaml:"by_monthday,omitempty"`
+	Users        []string   `json:"users,omitempty" yaml:"users,omitempty"`
+	RollingUsers [][]string `json:"rolling_users,omitempty" yaml:"rolling_users,omitempty"`
+}
+
+// UserGroupConfig is Slack user group
+type UserGroupConfig struct {
+	Handle string `json:"handle" yaml:"handle"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// CustomActionConfig is custom action without its credentials
+type CustomActionConfig struct {
+	Name                string `json:"name" yaml:"name"`
+	Integration         string `json:"integration,omitempty" yaml:"integration,omitempty"`
+	Url                 string `json:"url" yaml:"url"`
+	HttpMethod          string `json:"http_method,omitempty" yaml:"http_method,omitempty"`
+	Headers             string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Data                string `json:"data,omitempty" yaml:"data,omitempty"`
+	ForwardWholePayload bool   `json:"forward_whole_payload,omitempty" yaml:"forward_whole_payload,omitempty"`
+}
+
+// Validate checks that config objects are identifiable and complete
+func (config *Config) Validate() error {
+	integrations := make(map[string]bool)
+	for _, integration := range config.Integrations {
+		if integration.Name == "" {
+			return fmt.Errorf("integration name required")
+		}
+		if integrations[integration.Name] {
+			return fmt.Errorf("duplicate integration %q", integration.Name)
+		}
+		integrations[integration.Name] = true
+		if integration.Type == "" {
+			return fmt.Errorf("type of integration %q required", integration.Name)
+		}
+
+		if route := integration.DefaultRoute; route != nil {
+			if route.RoutingRegex != "" {
+				return fmt.Errorf("default route of integration %q can't have routing regex", integration.Name)
+			}
+			if err := validateEscalations(route.Escalations); err != nil {
+				return fmt.Errorf("default route of integration %q: %w", integration.Name, err)
+			}
+		}
+		routes := make(map[string]bool)
+		for _, route := range integration.Routes {
+			if route.RoutingRegex == "" {
+				return fmt.Errorf("routing regex of integration %q route required", integration.Name)
+			}
+			if routes[route.RoutingRegex] {
+				return fmt.Errorf("duplicate route %q of integration %q", route.RoutingRegex, integration.Name)
+			}
+			routes[route.RoutingRegex] = true
+			if err := validateEscalations(route.Escalations); err != nil {
+				return fmt.Errorf("route %q of integration %q: %w", route.RoutingRegex, integration.Name, err)
+			}
+		}
+	}
+
+	schedules := make(map[string]bool)
+	for _, schedule := range config.Schedules {
+		if schedule.Name == "" {
+			return fmt.Errorf("schedule name required")
+		}
+		if schedules[schedule.Name] {
+			return fmt.Errorf("duplicate schedule %q", schedule.Name)
+		}
+		schedules[schedule.Name] = true
+		if schedule.ICalUrl != "" && len(schedule.Shifts) > 0 {
+			return fmt.Errorf("ical schedule %q can't have shifts", schedule.Name)
+		}
+
+		shifts := make(map[string]bool)
+		for _, shift := range schedule.Shifts {
+			if shift.Name == "" {
+				return fmt.Errorf("name of schedule %q shift required", schedule.Name)
+			}
+			if shifts[shift.Name] {
+				return fmt.Errorf("duplicate shift %q of schedule %q", shift.Name, schedule.Name)
+			}
+			shifts[shift.Name] = true
+			if shift.Type == "" {
+				return fmt.Errorf("type of schedule %q shift %q required", schedule.Name, shift.Name)
+			}
+			if _, err := time.Parse(onCallShiftTimeLayout, shift.Start); err != nil {
+				return fmt.Errorf("invalid start %q of schedule %q shift %q", shift.Start, schedule.Name, shift.Name)
+			}
+		}
+	}
+
+	chains := make(map[string]bool)
+	for _, chain := range config.EscalationChains {
+		if chain.Name == "" {
+			return fmt.Errorf("escalation chain name required")
+		}
+		if chains[chain.Name] {
+			return fmt.Errorf("duplicate escalation chain %q", chain.Name)
+		}
+		chains[chain.Name] = true
+		if err := validateEscalations(chain.Escalations); err != nil {
+			return fmt.Errorf("escalation chain %q: %w", chain.Name, err)
+		}
+	}
+
+	actions := make(map[string]bool)
+	for _, action := range config.CustomActions {
+		if action.Name == "" {
+			return fmt.Errorf("custom action name required")
+		}
+		if actions[action.Name] {
+			return fmt.Errorf("duplicate custom action %q", action.Name)
+		}
+		actions[action.Name] = true
+		if action.Url == "" {
+			return fmt.Errorf("url of custom action %q required", action.Name)
+		}
+	}
+	return nil
+}
+
+func validateEscalations(escalations []*EscalationConfig) error {
+	for i, escalation := range escalations {
+		if escalation.Type == "" {
+			return fmt.Errorf("type of escalation %d required", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Drift statuses of configuration objects
+const (
+	// DriftMissing object is in the config but not in live state
+	DriftMissing = "missing"
+	// DriftChanged object has live fields different from the config
+	DriftChanged = "changed"
+	// DriftUnexpected object is in live state but not in the config
+	DriftUnexpected = "unexpected"
+)
+
+// Drift is a difference of configuration object from live state
+type Drift struct {
+	Status string `json:"status"`
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	// ID of the live object, empty for missing objects
+	ID    string       `json:"id,omitempty"`
+	Diffs []*FieldDiff `json:"diffs,omitempty"`
+}
+
+// DriftReport lists differences between config and live state
+type DriftReport struct {
+	Drifts []*Drift `json:"drifts"`
+}
+
+// DriftOptions configures drift detection
+type DriftOptions struct {
+	// ResolveReferences resolves human references of exported config first, see ConfigService.ResolveReferences
+	ResolveReferences bool
+	// Unmanaged reports live integrations, schedules and escalation chains missing in the config
+	Unmanaged bool
+}
+
+// Drifted reports whether live state differs from the config
+func (report *DriftReport) Drifted() bool {
+	return len(report.Drifts) > 0
+}
+
+// ExitCode is 0 without drift and 2 with drift, leaving 1 for errors
+func (report *DriftReport) ExitCode() int {
+	if report.Drifted() {
+		return 2
+	}
+	return 0
+}
+
+// String formats the report for humans, one object per line followed by its differing fields
+func (report *DriftReport) String() string {
+	if !report.Drifted() {
+		return "No drift\n"
+	}
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, drift := range report.Drifts {
+		counts[drift.Status]++
+		sign := map[string]string{DriftMissing: "+", DriftChanged: "~", DriftUnexpected: "-"}[drift.Status]
+		fmt.Fprintf(&b, "%s %s %s", sign, drift.Status, drift.Path)
+		if drift.ID != "" {
+			fmt.Fprintf(&b, " (%s)", drift.ID)
+		}
+		b.WriteString("\n")
+		for _, diff := range drift.Diffs {
+			if drift.Status == DriftMissing {
+				fmt.Fprintf(&b, "    %s: %s\n", diff.Field, diff.New)
+			} else if diff.Immutable {
+				fmt.Fprintf(&b, "    %s: %s -> %s (immutable)\n", diff.Field, diff.Old, diff.New)
+			} else {
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", diff.Field, diff.Old, diff.New)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "%d missing, %d changed, %d unexpected\n", counts[DriftMissing], counts[DriftChanged], counts[DriftUnexpected])
+	return b.String()
+}
+
+// WriteJSON writes the report as JSON document
+func (report *DriftReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// DetectDrift compares the config with live state without changing it. Objects are matched the same way
+// as by Plan, so a route with changed routing regex is reported as missing and unexpected one.
+// Objects within missing or unexpected ones aren't reported. Changes of immutable fields, which Plan
+// refuses, are reported as changed objects with immutable diffs.
+func (service *ConfigService) DetectDrift(config *Config, opt *DriftOptions, options ...RequestOption) (*DriftReport, error) {
+	if opt == nil {
+		opt = &DriftOptions{}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if opt.ResolveReferences {
+		resolved, err := service.ResolveReferences(config, options...)
+		if err != nil {
+			return nil, err
+		}
+		config = resolved
+	}
+	plan, err := service.plan(config, &PlanOptions{Prune: opt.Unmanaged}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	status := map[string]string{ChangeCreate: DriftMissing, ChangeUpdate: DriftChanged, ChangeDelete: DriftUnexpected}
+	var parents []string
+	for _, change := range plan.Changes {
+		if change.Action != ChangeUpdate {
+			parents = append(parents, change.Path+" ")
+		}
+	}
+	report := &DriftReport{Drifts: []*Drift{}}
+changes:
+	for _, change := range plan.Changes {
+		for _, parent := range parents {
+			if strings.HasPrefix(change.Path, parent) {
+				continue changes
+			}
+		}
+		report.Drifts = append(report.Drifts, &Drift{
+			Status: status[change.Action],
+			Kind:   change.Kind,
+			Path:   change.Path,
+			ID:     change.ID,
+			Diffs:  change.Diffs,
+		})
+	}
+	return report, nil
+}
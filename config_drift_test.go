@@ -0,0 +1,191 @@
+package amixr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestConfigDetectDrift(t *testing.T) {
+	api, client, done := setupConfigAPI(t, testConfigLive)
+	defer done()
+
+	report, err := client.Config.DetectDrift(testConfig(), &DriftOptions{Unmanaged: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `- unexpected schedule "Primary" shift "Old" (SH2)
+- unexpected integration "Grafana" route "us-west" escalation 2 (E3)
+- unexpected integration "Grafana" route "eu" (R2)
+~ changed schedule "Primary" shift "Days" (SH1)
+    duration: 28800 -> 36000
++ missing schedule "Primary" shift "Nights"
+    type: "recurrent_event"
+    start: "2020-09-04T19:00:00"
+    duration: 50400
+    users: ["U2"]
+    frequency: "daily"
++ missing schedule "Secondary"
+    time_zone: "Europe/Berlin"
+~ changed integration "Grafana" (I1)
+    templates.slack.title: "old" -> "new"
++ missing integration "Alertmanager"
+    type: "alertmanager"
++ missing integration "Grafana" route "us-east"
+    routing_regex: "us-east"
+~ changed integration "Grafana" route "us-west" (R1)
+    position: 0 -> 1
+~ changed integration "Grafana" route "us-west" escalation 1 (E2)
+    persons_to_notify: ["U1"] -> ["U1","U2"]
+    important: false -> true
+- unexpected schedule "Legacy" (SL1)
+4 missing, 4 changed, 4 unexpected
+`
+	if got := report.String(); got != want {
+		t.Errorf("drift is\n%s\nwant\n%s", got, want)
+	}
+	if report.ExitCode() != 2 {
+		t.Errorf("exit code with drift is %d", report.ExitCode())
+	}
+	if len(api.writes) != 0 {
+		t.Errorf("drift detection wrote %v", api.writes)
+	}
+
+	var b bytes.Buffer
+	if err := report.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var decoded DriftReport
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Drifts) != len(report.Drifts) || decoded.Drifts[0].Status != DriftUnexpected {
+		t.Errorf("decoded report is %s", b.String())
+	}
+
+	// unmanaged objects aren't reported by default
+	report, err = client.Config.DetectDrift(&Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() || report.ExitCode() != 0 || report.String() != "No drift\n" {
+		t.Errorf("drift of empty config is\n%s", report)
+	}
+}
+
+func TestConfigDetectDriftOfExport(t *testing.T) {
+	api, client, done := setupConfigAPI(t, testExportLive)
+	defer done()
+
+	// references which can't be resolved back
+	api.object("escalation_policies", "E1")["persons_to_notify"] = []interface{}{"U1"}
+	delete(api.object("routes", "RD1"), "slack")
+
+	config, err := client.Config.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := client.Config.DetectDrift(config, &DriftOptions{ResolveReferences: true, Unmanaged: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() {
+		t.Fatalf("export drifted from live state\n%s", report)
+	}
+
+	api.object("integrations", "I2")["templates"].(map[string]interface{})["slack"].(map[string]interface{})["title"] = "Edited"
+	api.object("routes", "R2")["routing_regex"] = "eu-west"
+	api.object("escalation_policies", "E1")["position"] = 1
+	api.object("escalation_policies", "E2")["position"] = 0
+	report, err = client.Config.DetectDrift(config, &DriftOptions{ResolveReferences: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `- unexpected integration "Grafana" route "eu-west" (R2)
+~ changed integration "Grafana" (I2)
+    templates.slack.title: "Edited" -> "Alert"
+~ changed integration "Grafana" route "us-west" escalation 0 (E1)
+    position: 1 -> 0
+~ changed integration "Grafana" route "us-west" escalation 1 (E2)
+    position: 0 -> 1
++ missing integration "Grafana" route "eu"
+    routing_regex: "eu"
+    position: 1
+    escalation_chain_id: "EC1"
+    slack.channel_id: "CHINCIDENTS"
+1 missing, 3 changed, 1 unexpected
+`
+	if got := report.String(); got != want {
+		t.Errorf("drift is\n%s\nwant\n%s", got, want)
+	}
+	if len(api.writes) != 0 {
+		t.Errorf("drift detection wrote %v", api.writes)
+	}
+}
+
+func TestConfigDetectDriftImmutable(t *testing.T) {
+	live := map[string]string{
+		"integrations": `[{"id": "I1", "name": "Grafana", "type": "grafana", "default_route_id": "RD1", "templates": null},
+			{"id": "I2", "name": "Alertmanager", "type": "alertmanager", "default_route_id": "RD2", "templates": null}]`,
+		"routes": `[{"id": "RD1", "integration_id": "I1", "position": 0, "routing_regex": "", "is_the_last_route": true},
+			{"id": "RD2", "integration_id": "I2", "position": 0, "routing_regex": "", "is_the_last_route": true}]`,
+		"schedules": `[{"id": "SP1", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []}]`,
+		"actions":   `[{"id": "A1", "name": "Restart", "integration_id": "I1", "url": "https://example.com/restart", "http_method": "POST"}]`,
+	}
+	tests := []struct {
+		name   string
+		config *Config
+		want   string
+	}{
+		{
+			name:   "integration type",
+			config: &Config{Integrations: []*IntegrationConfig{{Name: "Grafana", Type: "webhook"}}},
+			want: `~ changed integration "Grafana" (I1)
+    type: "grafana" -> "webhook" (immutable)
+0 missing, 1 changed, 0 unexpected
+`,
+		},
+		{
+			name:   "schedule type",
+			config: &Config{Schedules: []*ScheduleConfig{{Name: "Primary", ICalUrl: "https://example.com/on-call.ics"}}},
+			want: `~ changed schedule "Primary" (SP1)
+    type: "calendar" -> "ical" (immutable)
+    ical_url: "" -> "https://example.com/on-call.ics"
+0 missing, 1 changed, 0 unexpected
+`,
+		},
+		{
+			name: "custom action integration",
+			config: &Config{CustomActions: []*CustomActionConfig{
+				{Name: "Restart", Integration: "I2", Url: "https://example.com/restart", HttpMethod: "POST"},
+			}},
+			want: `~ changed custom action "Restart" (A1)
+    integration_id: "I1" -> "I2" (immutable)
+0 missing, 1 changed, 0 unexpected
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, client, done := setupConfigAPI(t, live)
+			defer done()
+
+			report, err := client.Config.DetectDrift(tt.config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := report.String(); got != tt.want {
+				t.Errorf("drift is\n%s\nwant\n%s", got, tt.want)
+			}
+			if report.ExitCode() != 2 || !report.Drifts[0].Diffs[0].Immutable {
+				t.Errorf("drift isn't reported as immutable change: %+v", report.Drifts[0])
+			}
+			if _, err := client.Config.Plan(tt.config, nil); err == nil {
+				t.Error("plan of immutable change succeeded")
+			}
+			if len(api.writes) != 0 {
+				t.Errorf("drift detection wrote %v", api.writes)
+			}
+		})
+	}
+}
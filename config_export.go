@@ -0,0 +1,317 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Export reads integrations with their routes and escalations, schedules with their on-call shifts,
+// escalation chains with their escalations, user groups, custom actions and Slack channels into a config. Ids are replaced with human references:
+// users with emails, schedules and escalation chains with names, Slack channels with names,
+// user groups with handles and custom actions with names. Ids which can't be resolved are kept.
+// Objects are ordered by name, routes and escalations by position, so exports of the same state are equal.
+// Credentials of custom actions aren't exported. Import resolves references back to ids.
+// Organizations with objects which can't be matched by Plan, like integrations with the same name,
+// can't be exported.
+func (service *ConfigService) Export(options ...RequestOption) (*Config, error) {
+	e := &exporter{}
+	if err := e.fetch(service.client, options); err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	for _, integration := range e.integrations {
+		config.Integrations = append(config.Integrations, e.integration(integration))
+	}
+	for _, schedule := range e.schedules {
+		config.Schedules = append(config.Schedules, e.schedule(schedule))
+	}
+	for _, chain := range e.chains {
+		config.EscalationChains = append(config.EscalationChains, &EscalationChainConfig{
+			Name:        chain.Name,
+			Escalations: e.escalations(e.chainEscalations[chain.ID]),
+		})
+	}
+	for _, group := range e.userGroups {
+		if group.SlackUserGroup != nil {
+			config.UserGroups = append(config.UserGroups, &UserGroupConfig{Handle: group.SlackUserGroup.Handle, Name: group.SlackUserGroup.Name})
+		}
+	}
+	for _, action := range e.customActions {
+		config.CustomActions = append(config.CustomActions, &CustomActionConfig{
+			Name:                action.Name,
+			Integration:         e.integrationNames.ref(action.IntegrationId),
+			Url:                 action.Url,
+			HttpMethod:          action.HttpMethod,
+			Headers:             stringValue(action.Headers),
+			Data:                stringValue(action.Data),
+			ForwardWholePayload: action.ForwardWholePayload,
+		})
+	}
+	for _, channel := range e.slackChannels {
+		config.SlackChannels = append(config.SlackChannels, channel.Name)
+	}
+
+	sort.SliceStable(config.Integrations, func(i, j int) bool { return config.Integrations[i].Name < config.Integrations[j].Name })
+	sort.SliceStable(config.Schedules, func(i, j int) bool { return config.Schedules[i].Name < config.Schedules[j].Name })
+	sort.SliceStable(config.EscalationChains, func(i, j int) bool { return config.EscalationChains[i].Name < config.EscalationChains[j].Name })
+	sort.SliceStable(config.UserGroups, func(i, j int) bool { return config.UserGroups[i].Handle < config.UserGroups[j].Handle })
+	sort.SliceStable(config.CustomActions, func(i, j int) bool { return config.CustomActions[i].Name < config.CustomActions[j].Name })
+	sort.Strings(config.SlackChannels)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("exported config can't be imported: %w", err)
+	}
+	return config, nil
+}
+
+// WriteYAML writes the config as YAML document
+func (config *Config) WriteYAML(w io.Writer) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteJSON writes the config as JSON document
+func (config *Config) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
+
+// references maps ids to human references
+type references map[string]string
+
+// ref returns reference of id, or id itself when it is unknown
+func (refs references) ref(id string) string {
+	if ref, ok := refs[id]; ok && ref != "" {
+		return ref
+	}
+	return id
+}
+
+func (refs references) refs(ids []string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = refs.ref(id)
+	}
+	return result
+}
+
+type exporter struct {
+	integrations  []*Integration
+	schedules     []*Schedule
+	chains        []*EscalationChain
+	userGroups    []*UserGroup
+	customActions []*CustomAction
+	slackChannels []*SlackChannel
+
+	// routes by integration id, escalations by route or escalation chain id and shifts by schedule id
+	routes           map[string][]*Route
+	routeEscalations map[string][]*Escalation
+	chainEscalations map[string][]*Escalation
+	shifts           map[string][]*OnCallShift
+
+	users, integrationNames, scheduleNames, chainNames, channels, groups, actions references
+}
+
+// fetch lists all objects at once and groups them client side, to keep number of requests
+// independent of number of routes and schedules
+func (e *exporter) fetch(client *Client, options []RequestOption) error {
+	var err error
+	if e.integrations, err = client.Integrations.ListAllIntegrations(&ListIntegrationOptions{}, options...); err != nil {
+		return err
+	}
+	routes, err := client.Routes.ListAllRoutes(&ListRouteOptions{}, options...)
+	if err != nil {
+		return err
+	}
+	escalations, err := client.Escalations.ListAllEscalations(&ListEscalationOptions{}, options...)
+	if err != nil {
+		return err
+	}
+	if e.schedules, err = client.Schedules.ListAllSchedules(&ListScheduleOptions{}, options...); err != nil {
+		return err
+	}
+	shifts, err := client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{}, options...)
+	if err != nil {
+		return err
+	}
+	users, err := client.Users.ListAllUsers(&ListUserOptions{}, options...)
+	if err != nil {
+		return err
+	}
+	if e.chains, err = client.EscalationChains.ListAllEscalationChains(&ListEscalationChainOptions{}, options...); err != nil {
+		return err
+	}
+	if e.userGroups, err = client.UserGroups.ListAllUserGroups(&ListUserGroupOptions{}, options...); err != nil {
+		return err
+	}
+	if e.customActions, err = client.CustomActions.ListAllCustomActions(&ListCustomActionOptions{}, options...); err != nil {
+		return err
+	}
+	if e.slackChannels, err = client.SlackChannels.ListAllSlackChannels(&ListSlackChannelOptions{}, options...); err != nil {
+		return err
+	}
+
+	e.routes = make(map[string][]*Route)
+	for _, route := range routes {
+		e.routes[route.IntegrationId] = append(e.routes[route.IntegrationId], route)
+	}
+	e.routeEscalations = make(map[string][]*Escalation)
+	e.chainEscalations = make(map[string][]*Escalation)
+	for _, escalation := range escalations {
+		if chainID := stringValue(escalation.EscalationChainId); chainID != "" {
+			e.chainEscalations[chainID] = append(e.chainEscalations[chainID], escalation)
+		} else {
+			e.routeEscalations[escalation.RouteId] = append(e.routeEscalations[escalation.RouteId], escalation)
+		}
+	}
+	e.shifts = make(map[string][]*OnCallShift)
+	for _, shift := range shifts {
+		e.shifts[shift.ScheduleId] = append(e.shifts[shift.ScheduleId], shift)
+	}
+
+	e.users = make(references)
+	for _, user := range users {
+		e.users[user.ID] = user.Email
+	}
+	e.integrationNames = make(references)
+	for _, integration := range e.integrations {
+		e.integrationNames[integration.ID] = integration.Name
+	}
+	e.scheduleNames = make(references)
+	for _, schedule := range e.schedules {
+		e.scheduleNames[schedule.ID] = schedule.Name
+	}
+	e.chainNames = make(references)
+	for _, chain := range e.chains {
+		e.chainNames[chain.ID] = chain.Name
+	}
+	e.channels = make(references)
+	for _, channel := range e.slackChannels {
+		e.channels[channel.SlackId] = channel.Name
+	}
+	e.groups = make(references)
+	for _, group := range e.userGroups {
+		if group.SlackUserGroup != nil {
+			e.groups[group.ID] = group.SlackUserGroup.Handle
+		}
+	}
+	e.actions = make(references)
+	for _, action := range e.customActions {
+		e.actions[action.ID] = action.Name
+	}
+	return nil
+}
+
+func (e *exporter) integration(integration *Integration) *IntegrationConfig {
+	config := &IntegrationConfig{Name: integration.Name, Type: integration.Type}
+	if t := integration.Templates; t != nil {
+		config.Templates = &TemplatesConfig{GroupingKey: t.GroupingKey, ResolveSignal: t.ResolveSignal}
+		if s := t.Slack; s != nil {
+			config.Templates.Slack = &SlackTemplateConfig{Title: s.Title, Message: s.Message, ImageURL: s.ImageURL}
+		}
+	}
+
+	routes := append([]*Route{}, e.routes[integration.ID]...)
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Position < routes[j].Position })
+	for _, route := range routes {
+		if route.IsTheLastRoute {
+			config.DefaultRoute = e.route(route)
+		} else {
+			config.Routes = append(config.Routes, e.route(route))
+		}
+	}
+	return config
+}
+
+func (e *exporter) route(route *Route) *RouteConfig {
+	config := &RouteConfig{EscalationChain: e.chainNames.ref(stringValue(route.EscalationChainId))}
+	// routing regex of the default route isn't used
+	if !route.IsTheLastRoute {
+		config.RoutingRegex = route.RoutingRegex
+	}
+	if route.SlackRoute != nil {
+		config.SlackChannel = e.channels.ref(stringValue(route.SlackRoute.ChannelId))
+	}
+
+	config.Escalations = e.escalations(e.routeEscalations[route.ID])
+	return config
+}
+
+// escalations returns escalations of a route or an escalation chain ordered by position
+func (e *exporter) escalations(live []*Escalation) []*EscalationConfig {
+	escalations := append([]*Escalation{}, live...)
+	sort.SliceStable(escalations, func(i, j int) bool { return escalations[i].Position < escalations[j].Position })
+	var configs []*EscalationConfig
+	for _, escalation := range escalations {
+		configs = append(configs, &EscalationConfig{
+			Type:                        stringValue(escalation.Type),
+			Duration:                    intValue(escalation.Duration),
+			PersonsToNotify:             e.users.refs(stringsValue(escalation.PersonsToNotify)),
+			PersonsToNotifyNextEachTime: e.users.refs(stringsValue(escalation.PersonsToNotifyEachTime)),
+			Schedule:                    e.scheduleNames.ref(stringValue(escalation.NotifyOnCallFromSchedule)),
+			GroupToNotify:               e.groups.ref(stringValue(escalation.GroupToNotify)),
+			ActionToTrigger:             e.actions.ref(stringValue(escalation.ActionToTrigger)),
+			Important:                   escalation.Important != nil && *escalation.Important,
+			NotifyIfTimeFrom:            stringValue(escalation.NotifyIfTimeFrom),
+			NotifyIfTimeTo:              stringValue(escalation.NotifyIfTimeTo),
+		})
+	}
+	return configs
+}
+
+func (e *exporter) schedule(schedule *Schedule) *ScheduleConfig {
+	config := &ScheduleConfig{
+		Name:     schedule.Name,
+		TimeZone: schedule.TimeZone,
+		ICalUrl:  stringValue(schedule.ICalUrl),
+	}
+	if schedule.Slack != nil {
+		config.SlackChannel = e.channels.ref(stringValue(schedule.Slack.ChannelId))
+	}
+	if schedule.Type == ScheduleTypeICal {
+		return config
+	}
+
+	for _, shift := range e.shifts[schedule.ID] {
+		shiftConfig := &OnCallShiftConfig{
+			Name:      shift.Name,
+			Type:      shift.Type,
+			Level:     shift.Level,
+			Start:     shift.Start,
+			Duration:  shift.Duration,
+			Frequency: stringValue(shift.Frequency),
+			Interval:  intValue(shift.Interval),
+			WeekStart: stringValue(shift.WeekStart),
+			Users:     e.users.refs(stringsValue(shift.Users)),
+		}
+		if shift.ByDay != nil && len(*shift.ByDay) > 0 {
+			shiftConfig.ByDay = *shift.ByDay
+		}
+		if shift.ByMonth != nil && len(*shift.ByMonth) > 0 {
+			shiftConfig.ByMonth = *shift.ByMonth
+		}
+		if shift.ByMonthday != nil && len(*shift.ByMonthday) > 0 {
+			shiftConfig.ByMonthday = *shift.ByMonthday
+		}
+		if shift.RollingUsers != nil {
+			for _, users := range *shift.RollingUsers {
+				shiftConfig.RollingUsers = append(shiftConfig.RollingUsers, e.users.refs(users))
+			}
+		}
+		config.Shifts = append(config.Shifts, shiftConfig)
+	}
+	sort.SliceStable(config.Shifts, func(i, j int) bool { return config.Shifts[i].Name < config.Shifts[j].Name })
+	return config
+}
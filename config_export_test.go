@@ -0,0 +1,213 @@
+package amixr
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var testExportLive = map[string]string{
+	"integrations": `[
+		{"id": "I2", "name": "Grafana", "type": "grafana", "default_route_id": "RD2",
+			"templates": {"grouping_key": "{{ payload.id }}", "resolve_signal": null, "slack": {"title": "Alert", "message": null, "image_url": null}}},
+		{"id": "I1", "name": "Alertmanager", "type": "alertmanager", "default_route_id": "RD1", "templates": null}
+	]`,
+	"routes": `[
+		{"id": "RD2", "integration_id": "I2", "position": 2, "routing_regex": "", "is_the_last_route": true},
+		{"id": "R2", "integration_id": "I2", "position": 1, "routing_regex": "eu", "is_the_last_route": false,
+			"escalation_chain_id": "EC1", "slack": {"channel_id": "CHINCIDENTS"}},
+		{"id": "R1", "integration_id": "I2", "position": 0, "routing_regex": "us-west", "is_the_last_route": false},
+		{"id": "RD1", "integration_id": "I1", "position": 0, "routing_regex": "", "is_the_last_route": true,
+			"slack": {"channel_id": "CHUNKNOWN"}}
+	]`,
+	"escalation_policies": `[
+		{"id": "E2", "route_id": "R1", "position": 1, "type": "notify_on_call_from_schedule", "notify_on_call_from_schedule": "SP1", "important": true},
+		{"id": "E1", "route_id": "R1", "position": 0, "type": "notify_persons", "persons_to_notify": ["U1", "UGONE"]},
+		{"id": "E3", "route_id": "R1", "position": 2, "type": "notify_user_group", "group_to_notify": "G1"},
+		{"id": "E4", "route_id": "R1", "position": 3, "type": "trigger_action", "action_to_trigger": "A1"},
+		{"id": "ED1", "route_id": "RD1", "position": 0, "type": "wait", "duration": 300},
+		{"id": "EE2", "escalation_chain_id": "EC1", "position": 1, "type": "notify_persons", "persons_to_notify": ["U2"]},
+		{"id": "EE1", "escalation_chain_id": "EC1", "position": 0, "type": "notify_on_call_from_schedule", "notify_on_call_from_schedule": "SP1"}
+	]`,
+	"schedules": `[
+		{"id": "SP1", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": [], "slack": {"channel_id": "CHINCIDENTS"}},
+		{"id": "SI1", "type": "ical", "name": "Holidays", "time_zone": "UTC", "ical_url": "https://example.com/holidays.ics", "on_call_now": []}
+	]`,
+	"on_call_shifts": `[
+		{"id": "SH2", "schedule_id": "SP1", "type": "rolling_users", "name": "Weekly", "level": 1, "start": "2020-09-07T00:00:00",
+			"duration": 604800, "frequency": "weekly", "interval": 1, "week_start": "MO", "by_day": ["MO"], "rolling_users": [["U1"], ["U2"]]},
+		{"id": "SH1", "schedule_id": "SP1", "type": "recurrent_event", "name": "Days", "level": 0, "start": "2020-09-04T09:00:00",
+			"duration": 28800, "frequency": "daily", "interval": 1, "users": ["U2"]}
+	]`,
+	"users": `[
+		{"id": "U1", "email": "alice@example.com", "username": "alice"},
+		{"id": "U2", "email": "bob@example.com", "username": "bob"}
+	]`,
+	"escalation_chains": `[{"id": "EC1", "name": "Europe"}]`,
+	"user_groups":       `[{"id": "G1", "type": "slack_based", "slack": {"id": "S1", "name": "SRE team", "handle": "sre"}}]`,
+	"actions": `[
+		{"id": "A1", "name": "Restart", "integration_id": "I2", "url": "https://example.com/restart", "http_method": "POST",
+			"headers": null, "data": "{}", "user": "admin", "password": "secret", "authorization_header": null, "forward_whole_payload": false}
+	]`,
+	"slack_channels": `[{"name": "incidents", "slack_id": "CHINCIDENTS"}, {"name": "general", "slack_id": "CHGENERAL"}]`,
+}
+
+const testExportYAML = `integrations:
+- name: Alertmanager
+  type: alertmanager
+  default_route:
+    slack_channel: CHUNKNOWN
+    escalations:
+    - type: wait
+      duration: 300
+- name: Grafana
+  type: grafana
+  templates:
+    grouping_key: '{{ payload.id }}'
+    slack:
+      title: Alert
+  default_route: {}
+  routes:
+  - routing_regex: us-west
+    escalations:
+    - type: notify_persons
+      persons_to_notify:
+      - alice@example.com
+      - UGONE
+    - type: notify_on_call_from_schedule
+      notify_on_call_from_schedule: Primary
+      important: true
+    - type: notify_user_group
+      group_to_notify: sre
+    - type: trigger_action
+      action_to_trigger: Restart
+  - routing_regex: eu
+    escalation_chain: Europe
+    slack_channel: incidents
+schedules:
+- name: Holidays
+  time_zone: UTC
+  ical_url: https://example.com/holidays.ics
+- name: Primary
+  time_zone: UTC
+  slack_channel: incidents
+  shifts:
+  - name: Days
+    type: recurrent_event
+    start: 2020-09-04T09:00:00
+    duration: 28800
+    frequency: daily
+    interval: 1
+    users:
+    - bob@example.com
+  - name: Weekly
+    type: rolling_users
+    level: 1
+    start: 2020-09-07T00:00:00
+    duration: 604800
+    frequency: weekly
+    interval: 1
+    week_start: MO
+    by_day:
+    - MO
+    rolling_users:
+    - - alice@example.com
+    - - bob@example.com
+escalation_chains:
+- name: Europe
+  escalations:
+  - type: notify_on_call_from_schedule
+    notify_on_call_from_schedule: Primary
+  - type: notify_persons
+    persons_to_notify:
+    - bob@example.com
+user_groups:
+- handle: sre
+  name: SRE team
+custom_actions:
+- name: Restart
+  integration: Grafana
+  url: https://example.com/restart
+  http_method: POST
+  data: '{}'
+slack_channels:
+- general
+- incidents
+`
+
+func TestConfigExport(t *testing.T) {
+	api, client, teardown := setupConfigAPI(t, testExportLive)
+	defer teardown()
+
+	config, err := client.Config.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(api.writes) > 0 {
+		t.Errorf("export wrote %v", api.writes)
+	}
+
+	var b bytes.Buffer
+	if err := config.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != testExportYAML {
+		t.Errorf("exported YAML is\n%s\nwant\n%s", got, testExportYAML)
+	}
+
+	// the same state is exported the same way
+	again, err := client.Config.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, again) {
+		t.Error("exports of the same state differ")
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("exported config is invalid: %v", err)
+	}
+
+	// routing regex of the default route isn't exported
+	api.object("routes", "RD1")["routing_regex"] = ".*"
+	config, err = client.Config.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regex := config.Integrations[0].DefaultRoute.RoutingRegex; regex != "" {
+		t.Errorf("default route is exported with routing regex %q", regex)
+	}
+
+	// duplicate names can't be imported back
+	api.object("schedules", "SI1")["name"] = "Primary"
+	if _, err := client.Config.Export(); err == nil || err.Error() != `exported config can't be imported: duplicate schedule "Primary"` {
+		t.Errorf("export with duplicate names returned %v", err)
+	}
+}
+
+func TestConfigWriteJSON(t *testing.T) {
+	title := "Alert"
+	config := &Config{
+		Integrations: []*IntegrationConfig{{
+			Name:      "Grafana",
+			Type:      "grafana",
+			Templates: &TemplatesConfig{Slack: &SlackTemplateConfig{Title: &title}},
+			Routes:    []*RouteConfig{{RoutingRegex: "eu", SlackChannel: "incidents"}},
+		}},
+		SlackChannels: []string{"incidents"},
+	}
+	var b bytes.Buffer
+	if err := config.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b.String(), err)
+	}
+	if !reflect.DeepEqual(config, &got) {
+		t.Errorf("decoded config differs from written one:\n%s", b.String())
+	}
+	if !bytes.Contains(b.Bytes(), []byte(`  "integrations": [`)) {
+		t.Errorf("JSON isn't indented:\n%s", b.String())
+	}
+}
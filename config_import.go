@@ -0,0 +1,296 @@
+package amixr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ParseConfig reads config from YAML or JSON document, unknown fields are errors
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// MissingReferencesError lists references of config which don't exist in the organization
+type MissingReferencesError struct {
+	// References are like user "alice@example.com" or slack channel "incidents"
+	References []string
+}
+
+func (err *MissingReferencesError) Error() string {
+	return fmt.Sprintf("missing references: %s", strings.Join(err.References, ", "))
+}
+
+// ResolveReferences returns copy of the config with human references of exported config replaced
+// with ids of the organization: users are found by email, Slack channels by name, user groups by handle
+// and integrations, schedules, escalation chains and custom actions missing in the config by name.
+// References to objects of the config are kept for Plan, which creates them before their referrers,
+// so only references missing both in the config and in the organization are reported at once
+// with MissingReferencesError.
+func (service *ConfigService) ResolveReferences(config *Config, options ...RequestOption) (*Config, error) {
+	resolved, err := copyConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{client: service.client, options: options, names: make(map[string]bool), ids: make(map[string]string), missing: make(map[string]bool)}
+	for _, integration := range resolved.Integrations {
+		r.names[integrationPath(integration.Name)] = true
+	}
+	for _, schedule := range resolved.Schedules {
+		r.names[schedulePath(schedule.Name)] = true
+	}
+	for _, chain := range resolved.EscalationChains {
+		r.names[chainPath(chain.Name)] = true
+	}
+	for _, action := range resolved.CustomActions {
+		r.names[actionPath(action.Name)] = true
+	}
+
+	for _, integration := range resolved.Integrations {
+		routes := integration.Routes
+		if integration.DefaultRoute != nil {
+			routes = append([]*RouteConfig{integration.DefaultRoute}, routes...)
+		}
+		for _, route := range routes {
+			route.EscalationChain = r.escalationChain(route.EscalationChain)
+			route.SlackChannel = r.slackChannel(route.SlackChannel)
+			r.escalations(route.Escalations)
+		}
+	}
+	for _, chain := range resolved.EscalationChains {
+		r.escalations(chain.Escalations)
+	}
+	for _, action := range resolved.CustomActions {
+		action.Integration = r.integration(action.Integration)
+	}
+	for _, schedule := range resolved.Schedules {
+		schedule.SlackChannel = r.slackChannel(schedule.SlackChannel)
+		for _, shift := range schedule.Shifts {
+			shift.Users = r.users(shift.Users)
+			for i, users := range shift.RollingUsers {
+				shift.RollingUsers[i] = r.users(users)
+			}
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.missing) > 0 {
+		err := &MissingReferencesError{}
+		for ref := range r.missing {
+			err.References = append(err.References, ref)
+		}
+		sort.Strings(err.References)
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// Import creates or updates integrations with their routes and escalations, schedules with their
+// on-call shifts, custom actions and escalation chains with their escalations of exported config.
+// References are resolved before any change, so nothing is written when some are missing. It returns the applied plan.
+func (service *ConfigService) Import(config *Config, opt *PlanOptions, options ...RequestOption) (*Plan, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	resolved, err := service.ResolveReferences(config, options...)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := service.Plan(resolved, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+	return plan, service.Apply(plan, options...)
+}
+
+// copyConfig deep copies config, so resolving references doesn't change it
+func copyConfig(config *Config) (*Config, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	copied := &Config{}
+	if err := json.Unmarshal(data, copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// resolver finds ids of references once, remembering missing ones and the first request error
+type resolver struct {
+	client  *Client
+	options []RequestOption
+	// names are paths of named config objects, references to them are kept
+	names map[string]bool
+	// ids by kind and reference
+	ids     map[string]string
+	missing map[string]bool
+	err     error
+}
+
+func (r *resolver) resolve(kind, ref string, lookup func() (string, bool, error)) string {
+	if ref == "" {
+		return ""
+	}
+	key := fmt.Sprintf("%s %q", kind, ref)
+	if id, ok := r.ids[key]; ok {
+		return id
+	}
+	if r.err != nil {
+		return ref
+	}
+	id, ok, err := lookup()
+	if err != nil {
+		r.err = err
+		return ref
+	}
+	if !ok {
+		r.missing[key] = true
+		id = ref
+	}
+	r.ids[key] = id
+	return id
+}
+
+func (r *resolver) escalations(escalations []*EscalationConfig) {
+	for _, escalation := range escalations {
+		escalation.PersonsToNotify = r.users(escalation.PersonsToNotify)
+		escalation.PersonsToNotifyNextEachTime = r.users(escalation.PersonsToNotifyNextEachTime)
+		escalation.Schedule = r.schedule(escalation.Schedule)
+		escalation.GroupToNotify = r.userGroup(escalation.GroupToNotify)
+		escalation.ActionToTrigger = r.customAction(escalation.ActionToTrigger)
+	}
+}
+
+func (r *resolver) users(emails []string) []string {
+	if len(emails) == 0 {
+		return emails
+	}
+	ids := make([]string, len(emails))
+	for i, email := range emails {
+		ids[i] = r.resolve("user", email, func() (string, bool, error) {
+			users, err := r.client.Users.ListAllUsers(&ListUserOptions{Email: email}, r.options...)
+			if err != nil {
+				return "", false, err
+			}
+			for _, user := range users {
+				if strings.EqualFold(user.Email, email) {
+					return user.ID, true, nil
+				}
+			}
+			return "", false, nil
+		})
+	}
+	return ids
+}
+
+func (r *resolver) slackChannel(name string) string {
+	return r.resolve("slack channel", name, func() (string, bool, error) {
+		channels, err := r.client.SlackChannels.ListAllSlackChannels(&ListSlackChannelOptions{ChannelName: name}, r.options...)
+		if err != nil {
+			return "", false, err
+		}
+		for _, channel := range channels {
+			if channel.Name == name {
+				return channel.SlackId, true, nil
+			}
+		}
+		return "", false, nil
+	})
+}
+
+// escalationChain keeps names of config escalation chains, Plan resolves them
+func (r *resolver) escalationChain(name string) string {
+	if r.names[chainPath(name)] {
+		return name
+	}
+	return r.resolve("escalation chain", name, func() (string, bool, error) {
+		chains, err := r.client.EscalationChains.ListAllEscalationChains(&ListEscalationChainOptions{Name: name}, r.options...)
+		if err != nil {
+			return "", false, err
+		}
+		for _, chain := range chains {
+			if chain.Name == name {
+				return chain.ID, true, nil
+			}
+		}
+		return "", false, nil
+	})
+}
+
+// schedule keeps names of config schedules, Plan resolves them
+func (r *resolver) schedule(name string) string {
+	if r.names[schedulePath(name)] {
+		return name
+	}
+	return r.resolve("schedule", name, func() (string, bool, error) {
+		schedules, err := r.client.Schedules.ListAllSchedules(&ListScheduleOptions{Name: name}, r.options...)
+		if err != nil {
+			return "", false, err
+		}
+		for _, schedule := range schedules {
+			if schedule.Name == name {
+				return schedule.ID, true, nil
+			}
+		}
+		return "", false, nil
+	})
+}
+
+func (r *resolver) userGroup(handle string) string {
+	return r.resolve("user group", handle, func() (string, bool, error) {
+		id, err := r.client.UserGroups.ResolveSlackHandle(handle, r.options...)
+		if errors.Is(err, ErrNotFound) {
+			return "", false, nil
+		}
+		return id, err == nil, err
+	})
+}
+
+// integration keeps names of config integrations, Plan resolves them
+func (r *resolver) integration(name string) string {
+	if r.names[integrationPath(name)] {
+		return name
+	}
+	return r.resolve("integration", name, func() (string, bool, error) {
+		integrations, err := r.client.Integrations.ListAllIntegrations(&ListIntegrationOptions{}, r.options...)
+		if err != nil {
+			return "", false, err
+		}
+		for _, integration := range integrations {
+			if integration.Name == name {
+				return integration.ID, true, nil
+			}
+		}
+		return "", false, nil
+	})
+}
+
+// customAction keeps names of config custom actions, Plan resolves them
+func (r *resolver) customAction(name string) string {
+	if r.names[actionPath(name)] {
+		return name
+	}
+	return r.resolve("custom action", name, func() (string, bool, error) {
+		actions, err := r.client.CustomActions.ListAllCustomActions(&ListCustomActionOptions{Name: name}, r.options...)
+		if err != nil {
+			return "", false, err
+		}
+		for _, action := range actions {
+			if action.Name == name {
+				return action.ID, true, nil
+			}
+		}
+		return "", false, nil
+	})
+}
@@ -0,0 +1,135 @@
+package amixr
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(testExportYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := config.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != testExportYAML {
+		t.Errorf("parsed YAML is written as\n%s\nwant\n%s", got, testExportYAML)
+	}
+
+	b.Reset()
+	if err := config.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ParseConfig(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, fromJSON) {
+		t.Errorf("config parsed from JSON differs:\n%s", b.String())
+	}
+
+	if _, err := ParseConfig([]byte("integrations:\n- name: Grafana\n  kind: grafana\n")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+var testImportTarget = map[string]string{
+	"users": `[
+		{"id": "TU1", "email": "alice@example.com", "username": "alice"},
+		{"id": "TU2", "email": "bob@example.com", "username": "bob"}
+	]`,
+	"slack_channels": `[{"name": "incidents", "slack_id": "TCINCIDENTS"}]`,
+	"user_groups":    `[{"id": "TG1", "type": "slack_based", "slack": {"id": "TS1", "name": "SRE team", "handle": "sre"}}]`,
+}
+
+func TestConfigImport(t *testing.T) {
+	api, client, teardown := setupConfigAPI(t, testImportTarget)
+	defer teardown()
+
+	config, err := ParseConfig([]byte(testExportYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Config.Import(config, nil)
+	var missing *MissingReferencesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected missing references error, got %v", err)
+	}
+	if want := []string{`slack channel "CHUNKNOWN"`, `user "UGONE"`}; !reflect.DeepEqual(missing.References, want) {
+		t.Errorf("missing references are %v, want %v", missing.References, want)
+	}
+	if len(api.writes) > 0 {
+		t.Fatalf("import with missing references wrote %v", api.writes)
+	}
+
+	config.Integrations[0].DefaultRoute.SlackChannel = ""
+	persons := &config.Integrations[1].Routes[0].Escalations[0].PersonsToNotify
+	*persons = (*persons)[:1]
+	resolved, err := client.Config.ResolveReferences(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Integrations[1].Routes[0].Escalations[0].PersonsToNotify[0]; got != "alice@example.com" {
+		t.Errorf("resolving changed the config to %q", got)
+	}
+	// chains and custom actions of the config are resolved by Plan
+	route := resolved.Integrations[1].Routes[1]
+	if route.EscalationChain != "Europe" || route.SlackChannel != "TCINCIDENTS" {
+		t.Errorf("route is resolved to chain %q channel %q", route.EscalationChain, route.SlackChannel)
+	}
+	escalations := resolved.Integrations[1].Routes[0].Escalations
+	if escalations[0].PersonsToNotify[0] != "TU1" || escalations[1].Schedule != "Primary" ||
+		escalations[2].GroupToNotify != "TG1" || escalations[3].ActionToTrigger != "Restart" {
+		t.Errorf("escalations are resolved to %v %q %q %q", escalations[0].PersonsToNotify, escalations[1].Schedule,
+			escalations[2].GroupToNotify, escalations[3].ActionToTrigger)
+	}
+	if shift := resolved.Schedules[1].Shifts[1]; !reflect.DeepEqual(shift.RollingUsers, [][]string{{"TU1"}, {"TU2"}}) {
+		t.Errorf("rolling users are resolved to %v", shift.RollingUsers)
+	}
+
+	plan, err := client.Config.Import(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != len(api.writes) {
+		t.Errorf("%d changes, %d writes %v", len(plan.Changes), len(api.writes), api.writes)
+	}
+
+	var escalation map[string]interface{}
+	for _, object := range api.objects["escalation_policies"] {
+		if object["type"] == EscalationTypeNotifyOnCallFromSchedule {
+			escalation = object
+		}
+	}
+	primary := api.objects["schedules"][1]
+	if primary["name"] != "Primary" || escalation == nil || escalation["notify_on_call_from_schedule"] != primary["id"] {
+		t.Errorf("escalation %v doesn't notify schedule %v", escalation, primary)
+	}
+	chain, action := api.objects["escalation_chains"][0], api.objects["actions"][0]
+	if chain["name"] != "Europe" || action["name"] != "Restart" {
+		t.Fatalf("created chain %v and custom action %v", chain, action)
+	}
+	for _, object := range api.objects["routes"] {
+		if object["routing_regex"] == "eu" && (object["position"] != 1.0 || object["manual_order"] != true || object["escalation_chain_id"] != chain["id"]) {
+			t.Errorf("route %v isn't at manual position 1 with chain %v", object, chain["id"])
+		}
+	}
+	for _, object := range api.objects["escalation_policies"] {
+		if object["type"] == EscalationTypeTriggerAction && object["action_to_trigger"] != action["id"] {
+			t.Errorf("escalation %v doesn't trigger custom action %v", object, action["id"])
+		}
+	}
+
+	again, err := client.Config.Plan(resolved, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Empty() {
+		t.Errorf("plan after import isn't empty:\n%s", again)
+	}
+}
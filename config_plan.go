@@ -0,0 +1,1397 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Plan change actions
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Kinds of configuration objects
+const (
+	ConfigKindIntegration     = "integration"
+	ConfigKindRoute           = "route"
+	ConfigKindEscalation      = "escalation"
+	ConfigKindEscalationChain = "escalation_chain"
+	ConfigKindCustomAction    = "custom_action"
+	ConfigKindSchedule        = "schedule"
+	ConfigKindOnCallShift     = "on_call_shift"
+)
+
+// FieldDiff is a changed field of configuration object, Old and New are JSON encoded values.
+// Old is empty for created objects.
+type FieldDiff struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new"`
+	// Immutable field can't be changed by Apply, the object has to be recreated
+	Immutable bool `json:"immutable,omitempty"`
+}
+
+// Change is a single create, update or delete of configuration object
+type Change struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	// Path identifies the object within config, e.g. integration "Grafana" route "us-west" escalation 0
+	Path string `json:"path"`
+	// ID of the live object, empty for objects created by the plan
+	ID    string       `json:"id,omitempty"`
+	Diffs []*FieldDiff `json:"diffs,omitempty"`
+
+	apply func(state *applyState) error
+}
+
+// Plan is an ordered list of changes making live state match the config.
+// Parents are created before children, children are deleted before parents.
+type Plan struct {
+	Changes []*Change `json:"changes"`
+
+	// ids are ids of live objects by path
+	ids map[string]string
+	// names are names of top level config objects by kind, other references are ids
+	names map[string]map[string]bool
+}
+
+// PlanOptions configures planning
+type PlanOptions struct {
+	// Prune deletes integrations, schedules and escalation chains missing in the config
+	Prune bool
+}
+
+// Empty reports whether live state already matches the config
+func (plan *Plan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// String formats the plan for humans, one change per line followed by its changed fields
+func (plan *Plan) String() string {
+	if plan.Empty() {
+		return "No changes\n"
+	}
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+		sign := map[string]string{ChangeCreate: "+", ChangeUpdate: "~", ChangeDelete: "-"}[change.Action]
+		fmt.Fprintf(&b, "%s %s %s", sign, change.Action, change.Path)
+		if change.ID != "" {
+			fmt.Fprintf(&b, " (%s)", change.ID)
+		}
+		b.WriteString("\n")
+		for _, diff := range change.Diffs {
+			if change.Action == ChangeCreate {
+				fmt.Fprintf(&b, "    %s: %s\n", diff.Field, diff.New)
+			} else {
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", diff.Field, diff.Old, diff.New)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "%d to create, %d to update, %d to delete\n", counts[ChangeCreate], counts[ChangeUpdate], counts[ChangeDelete])
+	return b.String()
+}
+
+// Plan fetches live integrations, schedules, custom actions and escalation chains and computes changes
+// making them match the config. Integrations, schedules, custom actions and escalation chains are matched
+// by name, routes by routing regex, escalations by content or position and on-call shifts by name, so live
+// objects with the same name or routing regex are an error. Objects missing
+// in the config are deleted within managed parents, top level ones except custom actions only with opt.Prune.
+// Objects are planned in dependency order: schedules and integrations, custom actions of integrations,
+// escalation chains with escalations notifying schedules and triggering actions, and finally routes
+// referring to chains. Changes of immutable fields, like integration type, are returned as error.
+func (service *ConfigService) Plan(config *Config, opt *PlanOptions, options ...RequestOption) (*Plan, error) {
+	plan, err := service.plan(config, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range plan.Changes {
+		if err := change.immutableError(); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// plan computes changes including ones of immutable fields, which DetectDrift reports
+func (service *ConfigService) plan(config *Config, opt *PlanOptions, options ...RequestOption) (*Plan, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if opt == nil {
+		opt = &PlanOptions{}
+	}
+	p := &planner{
+		client:  service.client,
+		options: options,
+		plan:    &Plan{Changes: []*Change{}, ids: make(map[string]string), names: make(map[string]map[string]bool)},
+	}
+	for kind := range namedPaths {
+		p.plan.names[kind] = make(map[string]bool)
+	}
+	for _, schedule := range config.Schedules {
+		p.plan.names[ConfigKindSchedule][schedule.Name] = true
+	}
+	for _, chain := range config.EscalationChains {
+		p.plan.names[ConfigKindEscalationChain][chain.Name] = true
+	}
+	for _, integration := range config.Integrations {
+		p.plan.names[ConfigKindIntegration][integration.Name] = true
+	}
+	for _, action := range config.CustomActions {
+		p.plan.names[ConfigKindCustomAction][action.Name] = true
+	}
+
+	schedules, err := service.client.Schedules.ListAllSchedules(&ListScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	chains, err := service.client.EscalationChains.ListAllEscalationChains(&ListEscalationChainOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	integrations, err := service.client.Integrations.ListAllIntegrations(&ListIntegrationOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := service.client.CustomActions.ListAllCustomActions(&ListCustomActionOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	liveSchedules := make(map[string]*Schedule)
+	for _, schedule := range schedules {
+		if duplicate := liveSchedules[schedule.Name]; duplicate != nil {
+			return nil, duplicateError(schedulePath(schedule.Name), duplicate.ID, schedule.ID)
+		}
+		liveSchedules[schedule.Name] = schedule
+	}
+	for _, desired := range config.Schedules {
+		if err := p.planSchedule(desired, liveSchedules[desired.Name]); err != nil {
+			return nil, err
+		}
+		delete(liveSchedules, desired.Name)
+	}
+	liveIntegrations := make(map[string]*Integration)
+	for _, integration := range integrations {
+		if duplicate := liveIntegrations[integration.Name]; duplicate != nil {
+			return nil, duplicateError(integrationPath(integration.Name), duplicate.ID, integration.ID)
+		}
+		liveIntegrations[integration.Name] = integration
+	}
+	for _, desired := range config.Integrations {
+		if err := p.planIntegration(desired, liveIntegrations[desired.Name]); err != nil {
+			return nil, err
+		}
+	}
+	liveActions := make(map[string]*CustomAction)
+	for _, action := range actions {
+		if duplicate := liveActions[action.Name]; duplicate != nil {
+			return nil, duplicateError(actionPath(action.Name), duplicate.ID, action.ID)
+		}
+		liveActions[action.Name] = action
+	}
+	for _, desired := range config.CustomActions {
+		if err := p.planAction(desired, liveActions[desired.Name]); err != nil {
+			return nil, err
+		}
+	}
+	liveChains := make(map[string]*EscalationChain)
+	for _, chain := range chains {
+		if duplicate := liveChains[chain.Name]; duplicate != nil {
+			return nil, duplicateError(chainPath(chain.Name), duplicate.ID, chain.ID)
+		}
+		liveChains[chain.Name] = chain
+	}
+	for _, desired := range config.EscalationChains {
+		if err := p.planChain(desired, liveChains[desired.Name]); err != nil {
+			return nil, err
+		}
+		delete(liveChains, desired.Name)
+	}
+	for _, desired := range config.Integrations {
+		if err := p.planRoutes(desired, liveIntegrations[desired.Name]); err != nil {
+			return nil, err
+		}
+		delete(liveIntegrations, desired.Name)
+	}
+
+	if opt.Prune {
+		for _, integration := range integrations {
+			if liveIntegrations[integration.Name] == integration {
+				if err := p.deleteIntegration(integration); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, chain := range chains {
+			if liveChains[chain.Name] == chain {
+				if err := p.deleteChain(chain); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, schedule := range schedules {
+			if liveSchedules[schedule.Name] == schedule {
+				if err := p.deleteSchedule(schedule); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	p.plan.Changes = append(append(append(p.plan.Changes, p.childDeletes...), p.upserts...), p.parentDeletes...)
+	return p.plan, nil
+}
+
+// Apply applies changes of the plan in order. Routes and escalations are written with manual order,
+// so they get exactly the planned positions. Planning the same config again after successful Apply
+// gives an empty plan.
+func (service *ConfigService) Apply(plan *Plan, options ...RequestOption) error {
+	state := &applyState{client: service.client, plan: plan, ids: make(map[string]string), options: options}
+	for path, id := range plan.ids {
+		state.ids[path] = id
+	}
+	for _, change := range plan.Changes {
+		if err := change.immutableError(); err != nil {
+			return err
+		}
+	}
+	for _, change := range plan.Changes {
+		if err := change.apply(state); err != nil {
+			return fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.Path, err)
+		}
+	}
+	return nil
+}
+
+// immutableError reports the first diff of the change which can't be applied
+func (change *Change) immutableError() error {
+	for _, diff := range change.Diffs {
+		if diff.Immutable {
+			return fmt.Errorf("%s of %s can't be changed from %s to %s", diff.Field, change.Path, diff.Old, diff.New)
+		}
+	}
+	return nil
+}
+
+type applyState struct {
+	client  *Client
+	plan    *Plan
+	ids     map[string]string
+	options []RequestOption
+}
+
+func (state *applyState) id(path string) (string, error) {
+	id, ok := state.ids[path]
+	if !ok || id == "" {
+		return "", fmt.Errorf("id of %s unknown", path)
+	}
+	return id, nil
+}
+
+// refID resolves reference to a name of config object of the kind or id of another object
+func (state *applyState) refID(kind, ref string) (string, error) {
+	if ref == "" || !state.plan.names[kind][ref] {
+		return ref, nil
+	}
+	return state.id(namedPaths[kind](ref))
+}
+
+type planner struct {
+	client  *Client
+	options []RequestOption
+	plan    *Plan
+
+	childDeletes, upserts, parentDeletes []*Change
+}
+
+func (p *planner) upsert(change *Change) {
+	p.upserts = append(p.upserts, change)
+}
+
+func (p *planner) planSchedule(desired *ScheduleConfig, live *Schedule) error {
+	path := schedulePath(desired.Name)
+	fields := scheduleConfigFields(desired)
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindSchedule, Path: path, Diffs: createDiffs(fields),
+			apply: func(state *applyState) error {
+				schedule, _, err := state.client.Schedules.CreateSchedule(&CreateScheduleOptions{
+					Name:     desired.Name,
+					ICalUrl:  optionalString(desired.ICalUrl),
+					TimeZone: desired.TimeZone,
+					Slack:    scheduleSlack(desired.SlackChannel, nil),
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = schedule.ID
+				return nil
+			}})
+		for _, shift := range desired.Shifts {
+			p.planShift(path, shift, nil)
+		}
+		return nil
+	}
+
+	p.plan.ids[path] = live.ID
+	desiredType, liveType := ScheduleTypeCalendar, ScheduleTypeCalendar
+	if desired.ICalUrl != "" {
+		desiredType = ScheduleTypeICal
+	}
+	if live.Type == ScheduleTypeICal {
+		liveType = ScheduleTypeICal
+	}
+	if desiredType != liveType {
+		diffs := append([]*FieldDiff{immutableDiff("type", liveType, desiredType)}, diffFields(fields, scheduleFields(live))...)
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindSchedule, Path: path, ID: live.ID, Diffs: diffs})
+		return nil
+	}
+	if diffs := diffFields(fields, scheduleFields(live)); len(diffs) > 0 {
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindSchedule, Path: path, ID: live.ID, Diffs: diffs,
+			apply: func(state *applyState) error {
+				timeZone := desired.TimeZone
+				if timeZone == "" {
+					timeZone = live.TimeZone
+				}
+				_, _, err := state.client.Schedules.UpdateSchedule(live.ID, &UpdateScheduleOptions{
+					Name:     desired.Name,
+					ICalUrl:  optionalString(desired.ICalUrl),
+					TimeZone: timeZone,
+					Slack:    scheduleSlack(desired.SlackChannel, live.Slack),
+				}, state.options...)
+				return err
+			}})
+	}
+	if live.Type == ScheduleTypeICal {
+		return nil
+	}
+
+	shifts, err := p.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: live.ID}, p.options...)
+	if err != nil {
+		return err
+	}
+	liveShifts := make(map[string]*OnCallShift)
+	for _, shift := range shifts {
+		if shift.ScheduleId == live.ID {
+			if duplicate := liveShifts[shift.Name]; duplicate != nil {
+				return duplicateError(shiftPath(path, shift.Name), duplicate.ID, shift.ID)
+			}
+			liveShifts[shift.Name] = shift
+		}
+	}
+	for _, shift := range desired.Shifts {
+		p.planShift(path, shift, liveShifts[shift.Name])
+		delete(liveShifts, shift.Name)
+	}
+	for _, shift := range shifts {
+		if liveShifts[shift.Name] == shift {
+			p.childDeletes = append(p.childDeletes, deleteShiftChange(shiftPath(path, shift.Name), shift))
+		}
+	}
+	return nil
+}
+
+func (p *planner) planShift(schedulePath string, desired *OnCallShiftConfig, live *OnCallShift) {
+	path := shiftPath(schedulePath, desired.Name)
+	fields := shiftConfigFields(desired)
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindOnCallShift, Path: path, Diffs: createDiffs(fields),
+			apply: func(state *applyState) error {
+				scheduleID, err := state.id(schedulePath)
+				if err != nil {
+					return err
+				}
+				opt := mergeShift(desired, &OnCallShift{})
+				shift, _, err := state.client.OnCallShifts.CreateOnCallShift(&CreateOnCallShiftOptions{
+					ScheduleId:   scheduleID,
+					Type:         opt.Type,
+					Name:         opt.Name,
+					Level:        opt.Level,
+					Start:        opt.Start,
+					Duration:     opt.Duration,
+					Frequency:    opt.Frequency,
+					Users:        opt.Users,
+					Interval:     opt.Interval,
+					WeekStart:    opt.WeekStart,
+					ByDay:        opt.ByDay,
+					ByMonth:      opt.ByMonth,
+					ByMonthday:   opt.ByMonthday,
+					Source:       opt.Source,
+					RollingUsers: opt.RollingUsers,
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = shift.ID
+				return nil
+			}})
+		return
+	}
+
+	p.plan.ids[path] = live.ID
+	if diffs := diffFields(fields, shiftFields(live)); len(diffs) > 0 {
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindOnCallShift, Path: path, ID: live.ID, Diffs: diffs,
+			apply: func(state *applyState) error {
+				_, _, err := state.client.OnCallShifts.UpdateOnCallShift(live.ID, mergeShift(desired, live), state.options...)
+				return err
+			}})
+	}
+}
+
+func (p *planner) planAction(desired *CustomActionConfig, live *CustomAction) error {
+	path := actionPath(desired.Name)
+	fields := actionConfigFields(desired, p.ref(ConfigKindIntegration, desired.Integration))
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindCustomAction, Path: path, Diffs: createDiffs(fields),
+			apply: func(state *applyState) error {
+				integrationID, err := state.refID(ConfigKindIntegration, desired.Integration)
+				if err != nil {
+					return err
+				}
+				action, _, err := state.client.CustomActions.CreateCustomAction(&CreateCustomActionOptions{
+					Name:                desired.Name,
+					IntegrationId:       integrationID,
+					Url:                 desired.Url,
+					HttpMethod:          desired.HttpMethod,
+					Headers:             optionalString(desired.Headers),
+					Data:                optionalString(desired.Data),
+					ForwardWholePayload: desired.ForwardWholePayload,
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = action.ID
+				return nil
+			}})
+		return nil
+	}
+
+	p.plan.ids[path] = live.ID
+	diffs := diffFields(fields, actionFields(live))
+	for _, diff := range diffs {
+		// custom action can't be moved to another integration
+		diff.Immutable = diff.Field == "integration_id"
+	}
+	if len(diffs) > 0 {
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindCustomAction, Path: path, ID: live.ID, Diffs: diffs,
+			apply: func(state *applyState) error {
+				// headers and data missing in the config aren't managed, so live ones are kept
+				headers, data := live.Headers, live.Data
+				if desired.Headers != "" {
+					headers = &desired.Headers
+				}
+				if desired.Data != "" {
+					data = &desired.Data
+				}
+				_, _, err := state.client.CustomActions.UpdateCustomAction(live.ID, &UpdateCustomActionOptions{
+					Name:                desired.Name,
+					Url:                 desired.Url,
+					HttpMethod:          desired.HttpMethod,
+					Headers:             headers,
+					Data:                data,
+					ForwardWholePayload: &desired.ForwardWholePayload,
+				}, state.options...)
+				return err
+			}})
+	}
+	return nil
+}
+
+func (p *planner) planChain(desired *EscalationChainConfig, live *EscalationChain) error {
+	path := chainPath(desired.Name)
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindEscalationChain, Path: path,
+			apply: func(state *applyState) error {
+				chain, _, err := state.client.EscalationChains.CreateEscalationChain(&CreateEscalationChainOptions{
+					Name: desired.Name,
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = chain.ID
+				return nil
+			}})
+		p.planEscalations(path, true, desired.Escalations, nil)
+		return nil
+	}
+
+	p.plan.ids[path] = live.ID
+	escalations, err := p.chainEscalations(live.ID)
+	if err != nil {
+		return err
+	}
+	p.planEscalations(path, true, desired.Escalations, escalations)
+	return nil
+}
+
+func (p *planner) planIntegration(desired *IntegrationConfig, live *Integration) error {
+	path := integrationPath(desired.Name)
+	fields := integrationConfigFields(desired)
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindIntegration, Path: path, Diffs: createDiffs(append([]configField{{"type", desired.Type}}, fields...)),
+			apply: func(state *applyState) error {
+				integration, _, err := state.client.Integrations.CreateIntegration(&CreateIntegrationOptions{
+					Name:      desired.Name,
+					Type:      desired.Type,
+					Templates: mergeTemplates(desired.Templates, nil),
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = integration.ID
+				state.ids[defaultRoutePath(path)] = integration.DefaultRouteId
+				return nil
+			}})
+		return nil
+	}
+
+	p.plan.ids[path] = live.ID
+	p.plan.ids[defaultRoutePath(path)] = live.DefaultRouteId
+	diffs := diffFields(fields, integrationFields(live))
+	if live.Type != desired.Type {
+		diffs = append([]*FieldDiff{immutableDiff("type", live.Type, desired.Type)}, diffs...)
+	}
+	if len(diffs) > 0 {
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindIntegration, Path: path, ID: live.ID, Diffs: diffs,
+			apply: func(state *applyState) error {
+				_, _, err := state.client.Integrations.UpdateIntegration(live.ID, &UpdateIntegrationOptions{
+					Name:      desired.Name,
+					Templates: mergeTemplates(desired.Templates, live.Templates),
+				}, state.options...)
+				return err
+			}})
+	}
+	return nil
+}
+
+// planRoutes plans routes of the integration planned by planIntegration
+func (p *planner) planRoutes(desired *IntegrationConfig, live *Integration) error {
+	path := integrationPath(desired.Name)
+	if live == nil {
+		if desired.DefaultRoute != nil {
+			p.planRoute(path, defaultRoutePath(path), -1, desired.DefaultRoute, nil, nil)
+		}
+		for i, route := range desired.Routes {
+			p.planRoute(path, routePath(path, route.RoutingRegex), i, route, nil, nil)
+		}
+		return nil
+	}
+
+	routes, err := p.client.Routes.ListAllRoutes(&ListRouteOptions{IntegrationId: live.ID}, p.options...)
+	if err != nil {
+		return err
+	}
+	var defaultRoute *Route
+	liveRoutes := make(map[string]*Route)
+	for _, route := range routes {
+		if route.IsTheLastRoute {
+			defaultRoute = route
+		} else {
+			if duplicate := liveRoutes[route.RoutingRegex]; duplicate != nil {
+				return duplicateError(routePath(path, route.RoutingRegex), duplicate.ID, route.ID)
+			}
+			liveRoutes[route.RoutingRegex] = route
+		}
+	}
+
+	if desired.DefaultRoute != nil && defaultRoute != nil {
+		escalations, err := p.liveEscalations(defaultRoute.ID)
+		if err != nil {
+			return err
+		}
+		p.planRoute(path, defaultRoutePath(path), -1, desired.DefaultRoute, defaultRoute, escalations)
+	}
+	for i, route := range desired.Routes {
+		var escalations []*Escalation
+		liveRoute := liveRoutes[route.RoutingRegex]
+		if liveRoute != nil {
+			if escalations, err = p.liveEscalations(liveRoute.ID); err != nil {
+				return err
+			}
+		}
+		p.planRoute(path, routePath(path, route.RoutingRegex), i, route, liveRoute, escalations)
+		delete(liveRoutes, route.RoutingRegex)
+	}
+	for _, route := range routes {
+		if !route.IsTheLastRoute && liveRoutes[route.RoutingRegex] == route {
+			changes, err := p.deleteRoute(routePath(path, route.RoutingRegex), route)
+			if err != nil {
+				return err
+			}
+			p.childDeletes = append(p.childDeletes, changes...)
+		}
+	}
+	return nil
+}
+
+// planRoute plans route at given position, the default route has position -1
+func (p *planner) planRoute(integrationPath, path string, position int, desired *RouteConfig, live *Route, liveEscalations []*Escalation) {
+	fields := routeConfigFields(desired, position, p.ref(ConfigKindEscalationChain, desired.EscalationChain))
+	switch {
+	case live == nil && position >= 0:
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindRoute, Path: path, Diffs: createDiffs(fields),
+			apply: func(state *applyState) error {
+				integrationID, err := state.id(integrationPath)
+				if err != nil {
+					return err
+				}
+				chainID, err := state.refID(ConfigKindEscalationChain, desired.EscalationChain)
+				if err != nil {
+					return err
+				}
+				route, _, err := state.client.Routes.CreateRoute(&CreateRouteOptions{
+					IntegrationId:     integrationID,
+					EscalationChainId: chainID,
+					Position:          &position,
+					RoutingRegex:      desired.RoutingRegex,
+					Slack:             routeSlack(desired.SlackChannel, nil),
+					ManualOrder:       true,
+				}, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = route.ID
+				return nil
+			}})
+	case live == nil:
+		// default route of created integration
+		if len(fields) > 0 {
+			p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindRoute, Path: path, Diffs: createDiffs(fields),
+				apply: func(state *applyState) error {
+					id, err := state.id(path)
+					if err != nil {
+						return err
+					}
+					chainID, err := state.refID(ConfigKindEscalationChain, desired.EscalationChain)
+					if err != nil {
+						return err
+					}
+					_, _, err = state.client.Routes.UpdateRoute(id, &UpdateRouteOptions{
+						EscalationChainId: optionalString(chainID),
+						Slack:             routeSlack(desired.SlackChannel, nil),
+					}, state.options...)
+					return err
+				}})
+		}
+	default:
+		p.plan.ids[path] = live.ID
+		if diffs := diffFields(fields, routeFields(live)); len(diffs) > 0 {
+			p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindRoute, Path: path, ID: live.ID, Diffs: diffs,
+				apply: func(state *applyState) error {
+					chainID, err := state.refID(ConfigKindEscalationChain, desired.EscalationChain)
+					if err != nil {
+						return err
+					}
+					opt := &UpdateRouteOptions{
+						EscalationChainId: optionalString(chainID),
+						RoutingRegex:      live.RoutingRegex,
+						Slack:             routeSlack(desired.SlackChannel, live.SlackRoute),
+					}
+					if position >= 0 {
+						opt.Position = &position
+						opt.ManualOrder = true
+					}
+					_, _, err = state.client.Routes.UpdateRoute(live.ID, opt, state.options...)
+					return err
+				}})
+		}
+	}
+
+	p.planEscalations(path, false, desired.Escalations, liveEscalations)
+}
+
+// planEscalations plans escalations of a route, or of an escalation chain when inChain is set.
+// Desired escalations are matched to live ones with the same content first, so a moved escalation
+// only changes its position, and the rest by position.
+func (p *planner) planEscalations(parentPath string, inChain bool, desired []*EscalationConfig, live []*Escalation) {
+	fields := make([][]configField, len(desired))
+	matched := make([]*Escalation, len(desired))
+	used := make(map[*Escalation]bool)
+	for i, escalation := range desired {
+		fields[i] = escalationConfigFields(escalation, i, p.ref(ConfigKindSchedule, escalation.Schedule), p.ref(ConfigKindCustomAction, escalation.ActionToTrigger))
+		if i < len(live) && len(diffFields(fields[i], escalationFields(live[i]))) == 0 {
+			matched[i], used[live[i]] = live[i], true
+		}
+	}
+	for i := range desired {
+		for _, escalation := range live {
+			if matched[i] == nil && !used[escalation] && onlyPositionDiffers(fields[i], escalationFields(escalation)) {
+				matched[i], used[escalation] = escalation, true
+			}
+		}
+	}
+	for i := range desired {
+		if matched[i] == nil && i < len(live) && !used[live[i]] {
+			matched[i], used[live[i]] = live[i], true
+		}
+	}
+
+	for i, escalation := range desired {
+		p.planEscalation(parentPath, inChain, i, escalation, fields[i], matched[i])
+	}
+	for i, escalation := range live {
+		if !used[escalation] {
+			p.childDeletes = append(p.childDeletes, deleteEscalationChange(escalationPath(parentPath, i), escalation))
+		}
+	}
+}
+
+func onlyPositionDiffers(desired, live []configField) bool {
+	for _, diff := range diffFields(desired, live) {
+		if diff.Field != "position" {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *planner) planEscalation(parentPath string, inChain bool, position int, desired *EscalationConfig, fields []configField, live *Escalation) {
+	path := escalationPath(parentPath, position)
+	if live == nil {
+		p.upsert(&Change{Action: ChangeCreate, Kind: ConfigKindEscalation, Path: path, Diffs: createDiffs(fields),
+			apply: func(state *applyState) error {
+				parentID, err := state.id(parentPath)
+				if err != nil {
+					return err
+				}
+				scheduleID, err := state.refID(ConfigKindSchedule, desired.Schedule)
+				if err != nil {
+					return err
+				}
+				actionID, err := state.refID(ConfigKindCustomAction, desired.ActionToTrigger)
+				if err != nil {
+					return err
+				}
+				opt := &CreateEscalationOptions{
+					Position:                    &position,
+					Type:                        &desired.Type,
+					Duration:                    desired.Duration,
+					PersonsToNotify:             optionalStrings(desired.PersonsToNotify),
+					PersonsToNotifyNextEachTime: optionalStrings(desired.PersonsToNotifyNextEachTime),
+					NotifyOnCallFromSchedule:    scheduleID,
+					ActionToTrigger:             actionID,
+					GroupToNotify:               desired.GroupToNotify,
+					ManualOrder:                 true,
+					Important:                   &desired.Important,
+					NotifyIfTimeFrom:            desired.NotifyIfTimeFrom,
+					NotifyIfTimeTo:              desired.NotifyIfTimeTo,
+				}
+				if inChain {
+					opt.EscalationChainId = parentID
+				} else {
+					opt.RouteId = parentID
+				}
+				escalation, _, err := state.client.Escalations.CreateEscalation(opt, state.options...)
+				if err != nil {
+					return err
+				}
+				state.ids[path] = escalation.ID
+				return nil
+			}})
+		return
+	}
+
+	p.plan.ids[path] = live.ID
+	if diffs := diffFields(fields, escalationFields(live)); len(diffs) > 0 {
+		p.upsert(&Change{Action: ChangeUpdate, Kind: ConfigKindEscalation, Path: path, ID: live.ID, Diffs: diffs,
+			apply: func(state *applyState) error {
+				scheduleID, err := state.refID(ConfigKindSchedule, desired.Schedule)
+				if err != nil {
+					return err
+				}
+				actionID, err := state.refID(ConfigKindCustomAction, desired.ActionToTrigger)
+				if err != nil {
+					return err
+				}
+				// empty lists are sent to clear persons
+				persons := append([]string{}, desired.PersonsToNotify...)
+				personsEachTime := append([]string{}, desired.PersonsToNotifyNextEachTime...)
+				_, _, err = state.client.Escalations.UpdateEscalation(live.ID, &UpdateEscalationOptions{
+					Position:                 &position,
+					Type:                     &desired.Type,
+					Duration:                 desired.Duration,
+					PersonsToNotify:          &persons,
+					PersonsToNotifyEachTime:  &personsEachTime,
+					NotifyOnCallFromSchedule: scheduleID,
+					ActionToTrigger:          actionID,
+					GroupToNotify:            desired.GroupToNotify,
+					ManualOrder:              true,
+					Important:                &desired.Important,
+					NotifyIfTimeFrom:         desired.NotifyIfTimeFrom,
+					NotifyIfTimeTo:           desired.NotifyIfTimeTo,
+				}, state.options...)
+				return err
+			}})
+	}
+}
+
+// ref returns id of referenced config object of the kind when it is known at planning time
+func (p *planner) ref(kind, ref string) string {
+	if p.plan.names[kind][ref] {
+		if id, ok := p.plan.ids[namedPaths[kind](ref)]; ok {
+			return id
+		}
+	}
+	return ref
+}
+
+func (p *planner) liveEscalations(routeID string) ([]*Escalation, error) {
+	return p.listEscalations(&ListEscalationOptions{RouteId: routeID})
+}
+
+func (p *planner) chainEscalations(chainID string) ([]*Escalation, error) {
+	return p.listEscalations(&ListEscalationOptions{EscalationChainId: chainID})
+}
+
+func (p *planner) listEscalations(opt *ListEscalationOptions) ([]*Escalation, error) {
+	escalations, err := p.client.Escalations.ListAllEscalations(opt, p.options...)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(escalations, func(i, j int) bool { return escalations[i].Position < escalations[j].Position })
+	return escalations, nil
+}
+
+// deleteRoute returns deletes of route escalations followed by the route delete
+func (p *planner) deleteRoute(path string, route *Route) ([]*Change, error) {
+	escalations, err := p.liveEscalations(route.ID)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*Change
+	for i, escalation := range escalations {
+		changes = append(changes, deleteEscalationChange(escalationPath(path, i), escalation))
+	}
+	return append(changes, &Change{Action: ChangeDelete, Kind: ConfigKindRoute, Path: path, ID: route.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.Routes.DeleteRoute(route.ID, &DeleteRouteOptions{}, state.options...)
+			return err
+		}}), nil
+}
+
+func (p *planner) deleteIntegration(integration *Integration) error {
+	path := integrationPath(integration.Name)
+	routes, err := p.client.Routes.ListAllRoutes(&ListRouteOptions{IntegrationId: integration.ID}, p.options...)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		if route.IsTheLastRoute {
+			escalations, err := p.liveEscalations(route.ID)
+			if err != nil {
+				return err
+			}
+			for i, escalation := range escalations {
+				p.parentDeletes = append(p.parentDeletes, deleteEscalationChange(escalationPath(defaultRoutePath(path), i), escalation))
+			}
+			continue
+		}
+		changes, err := p.deleteRoute(routePath(path, route.RoutingRegex), route)
+		if err != nil {
+			return err
+		}
+		p.parentDeletes = append(p.parentDeletes, changes...)
+	}
+	p.parentDeletes = append(p.parentDeletes, &Change{Action: ChangeDelete, Kind: ConfigKindIntegration, Path: path, ID: integration.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.Integrations.DeleteIntegration(integration.ID, &DeleteIntegrationOptions{}, state.options...)
+			return err
+		}})
+	return nil
+}
+
+func (p *planner) deleteChain(chain *EscalationChain) error {
+	path := chainPath(chain.Name)
+	escalations, err := p.chainEscalations(chain.ID)
+	if err != nil {
+		return err
+	}
+	for i, escalation := range escalations {
+		p.parentDeletes = append(p.parentDeletes, deleteEscalationChange(escalationPath(path, i), escalation))
+	}
+	p.parentDeletes = append(p.parentDeletes, &Change{Action: ChangeDelete, Kind: ConfigKindEscalationChain, Path: path, ID: chain.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.EscalationChains.DeleteEscalationChain(chain.ID, &DeleteEscalationChainOptions{}, state.options...)
+			return err
+		}})
+	return nil
+}
+
+func (p *planner) deleteSchedule(schedule *Schedule) error {
+	path := schedulePath(schedule.Name)
+	if schedule.Type != ScheduleTypeICal {
+		shifts, err := p.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: schedule.ID}, p.options...)
+		if err != nil {
+			return err
+		}
+		for _, shift := range shifts {
+			p.parentDeletes = append(p.parentDeletes, deleteShiftChange(shiftPath(path, shift.Name), shift))
+		}
+	}
+	p.parentDeletes = append(p.parentDeletes, &Change{Action: ChangeDelete, Kind: ConfigKindSchedule, Path: path, ID: schedule.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.Schedules.DeleteSchedule(schedule.ID, &DeleteScheduleOptions{}, state.options...)
+			return err
+		}})
+	return nil
+}
+
+func deleteEscalationChange(path string, escalation *Escalation) *Change {
+	return &Change{Action: ChangeDelete, Kind: ConfigKindEscalation, Path: path, ID: escalation.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.Escalations.DeleteEscalation(escalation.ID, &DeleteEscalationOptions{}, state.options...)
+			return err
+		}}
+}
+
+func deleteShiftChange(path string, shift *OnCallShift) *Change {
+	return &Change{Action: ChangeDelete, Kind: ConfigKindOnCallShift, Path: path, ID: shift.ID,
+		apply: func(state *applyState) error {
+			_, err := state.client.OnCallShifts.DeleteOnCallShift(shift.ID, &DeleteOnCallShiftOptions{}, state.options...)
+			return err
+		}}
+}
+
+// duplicateError reports live objects which can't be matched to the config, because they have the same path
+func duplicateError(path, id, duplicateID string) error {
+	return fmt.Errorf("duplicate live %s: %s and %s", path, id, duplicateID)
+}
+
+// namedPaths are path functions of top level config objects which are referred to by name
+var namedPaths = map[string]func(name string) string{
+	ConfigKindIntegration:     integrationPath,
+	ConfigKindSchedule:        schedulePath,
+	ConfigKindEscalationChain: chainPath,
+	ConfigKindCustomAction:    actionPath,
+}
+
+func integrationPath(name string) string {
+	return fmt.Sprintf("integration %q", name)
+}
+
+func defaultRoutePath(integrationPath string) string {
+	return integrationPath + " default route"
+}
+
+func routePath(integrationPath, routingRegex string) string {
+	return fmt.Sprintf("%s route %q", integrationPath, routingRegex)
+}
+
+func escalationPath(routePath string, position int) string {
+	return fmt.Sprintf("%s escalation %d", routePath, position)
+}
+
+func chainPath(name string) string {
+	return fmt.Sprintf("escalation chain %q", name)
+}
+
+func actionPath(name string) string {
+	return fmt.Sprintf("custom action %q", name)
+}
+
+func schedulePath(name string) string {
+	return fmt.Sprintf("schedule %q", name)
+}
+
+func shiftPath(schedulePath, name string) string {
+	return fmt.Sprintf("%s shift %q", schedulePath, name)
+}
+
+// configField is a normalized value of managed field, empty strings and slices are nil
+type configField struct {
+	name  string
+	value interface{}
+}
+
+// diffFields compares desired fields with the same live fields, other live fields aren't managed
+func diffFields(desired, live []configField) []*FieldDiff {
+	liveValues := make(map[string]interface{})
+	for _, field := range live {
+		liveValues[field.name] = field.value
+	}
+	var diffs []*FieldDiff
+	for _, field := range desired {
+		old := liveValues[field.name]
+		if !reflect.DeepEqual(field.value, old) {
+			diffs = append(diffs, &FieldDiff{Field: field.name, Old: fieldJSON(old), New: fieldJSON(field.value)})
+		}
+	}
+	return diffs
+}
+
+func immutableDiff(field string, old, desired interface{}) *FieldDiff {
+	return &FieldDiff{Field: field, Old: fieldJSON(old), New: fieldJSON(desired), Immutable: true}
+}
+
+// createDiffs lists fields of created object which are set
+func createDiffs(fields []configField) []*FieldDiff {
+	var diffs []*FieldDiff
+	for _, field := range fields {
+		if field.value != nil && !reflect.DeepEqual(field.value, reflect.Zero(reflect.TypeOf(field.value)).Interface()) {
+			diffs = append(diffs, &FieldDiff{Field: field.name, New: fieldJSON(field.value)})
+		}
+	}
+	return diffs
+}
+
+func fieldJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func integrationConfigFields(desired *IntegrationConfig) []configField {
+	var fields []configField
+	if t := desired.Templates; t != nil {
+		if t.GroupingKey != nil {
+			fields = append(fields, configField{"templates.grouping_key", *t.GroupingKey})
+		}
+		if t.ResolveSignal != nil {
+			fields = append(fields, configField{"templates.resolve_signal", *t.ResolveSignal})
+		}
+		if s := t.Slack; s != nil {
+			if s.Title != nil {
+				fields = append(fields, configField{"templates.slack.title", *s.Title})
+			}
+			if s.Message != nil {
+				fields = append(fields, configField{"templates.slack.message", *s.Message})
+			}
+			if s.ImageURL != nil {
+				fields = append(fields, configField{"templates.slack.image_url", *s.ImageURL})
+			}
+		}
+	}
+	return fields
+}
+
+func integrationFields(live *Integration) []configField {
+	t := live.Templates
+	if t == nil {
+		t = &Templates{}
+	}
+	s := t.Slack
+	if s == nil {
+		s = &SlackTemplate{}
+	}
+	return []configField{
+		{"templates.grouping_key", stringValue(t.GroupingKey)},
+		{"templates.resolve_signal", stringValue(t.ResolveSignal)},
+		{"templates.slack.title", stringValue(s.Title)},
+		{"templates.slack.message", stringValue(s.Message)},
+		{"templates.slack.image_url", stringValue(s.ImageURL)},
+	}
+}
+
+// routeConfigFields returns managed fields of route at position, -1 for the default route
+func routeConfigFields(desired *RouteConfig, position int, chain string) []configField {
+	var fields []configField
+	if position >= 0 {
+		fields = append(fields, configField{"routing_regex", desired.RoutingRegex}, configField{"position", position})
+	}
+	if chain != "" {
+		fields = append(fields, configField{"escalation_chain_id", chain})
+	}
+	if desired.SlackChannel != "" {
+		fields = append(fields, configField{"slack.channel_id", desired.SlackChannel})
+	}
+	return fields
+}
+
+func routeFields(live *Route) []configField {
+	var channel string
+	if live.SlackRoute != nil {
+		channel = stringValue(live.SlackRoute.ChannelId)
+	}
+	return []configField{
+		{"routing_regex", live.RoutingRegex},
+		{"position", live.Position},
+		{"escalation_chain_id", stringValue(live.EscalationChainId)},
+		{"slack.channel_id", channel},
+	}
+}
+
+func escalationConfigFields(desired *EscalationConfig, position int, schedule, action string) []configField {
+	return []configField{
+		{"position", position},
+		{"type", desired.Type},
+		{"duration", desired.Duration},
+		{"persons_to_notify", stringsValue(&desired.PersonsToNotify)},
+		{"persons_to_notify_next_each_time", stringsValue(&desired.PersonsToNotifyNextEachTime)},
+		{"notify_on_call_from_schedule", schedule},
+		{"group_to_notify", desired.GroupToNotify},
+		{"action_to_trigger", action},
+		{"important", desired.Important},
+		{"notify_if_time_from", desired.NotifyIfTimeFrom},
+		{"notify_if_time_to", desired.NotifyIfTimeTo},
+	}
+}
+
+func escalationFields(live *Escalation) []configField {
+	return []configField{
+		{"position", live.Position},
+		{"type", stringValue(live.Type)},
+		{"duration", intValue(live.Duration)},
+		{"persons_to_notify", stringsValue(live.PersonsToNotify)},
+		{"persons_to_notify_next_each_time", stringsValue(live.PersonsToNotifyEachTime)},
+		{"notify_on_call_from_schedule", stringValue(live.NotifyOnCallFromSchedule)},
+		{"group_to_notify", stringValue(live.GroupToNotify)},
+		{"action_to_trigger", stringValue(live.ActionToTrigger)},
+		{"important", live.Important != nil && *live.Important},
+		{"notify_if_time_from", stringValue(live.NotifyIfTimeFrom)},
+		{"notify_if_time_to", stringValue(live.NotifyIfTimeTo)},
+	}
+}
+
+func actionConfigFields(desired *CustomActionConfig, integration string) []configField {
+	fields := []configField{
+		{"integration_id", integration},
+		{"url", desired.Url},
+		{"forward_whole_payload", desired.ForwardWholePayload},
+	}
+	if desired.HttpMethod != "" {
+		fields = append(fields, configField{"http_method", desired.HttpMethod})
+	}
+	if desired.Headers != "" {
+		fields = append(fields, configField{"headers", desired.Headers})
+	}
+	if desired.Data != "" {
+		fields = append(fields, configField{"data", desired.Data})
+	}
+	return fields
+}
+
+func actionFields(live *CustomAction) []configField {
+	return []configField{
+		{"integration_id", live.IntegrationId},
+		{"url", live.Url},
+		{"forward_whole_payload", live.ForwardWholePayload},
+		{"http_method", live.HttpMethod},
+		{"headers", stringValue(live.Headers)},
+		{"data", stringValue(live.Data)},
+	}
+}
+
+func scheduleConfigFields(desired *ScheduleConfig) []configField {
+	fields := []configField{{"ical_url", desired.ICalUrl}}
+	if desired.TimeZone != "" {
+		fields = append(fields, configField{"time_zone", desired.TimeZone})
+	}
+	if desired.SlackChannel != "" {
+		fields = append(fields, configField{"slack.channel_id", desired.SlackChannel})
+	}
+	return fields
+}
+
+func scheduleFields(live *Schedule) []configField {
+	var channel string
+	if live.Slack != nil {
+		channel = stringValue(live.Slack.ChannelId)
+	}
+	return []configField{
+		{"ical_url", stringValue(live.ICalUrl)},
+		{"time_zone", live.TimeZone},
+		{"slack.channel_id", channel},
+	}
+}
+
+func shiftConfigFields(desired *OnCallShiftConfig) []configField {
+	fields := []configField{
+		{"type", desired.Type},
+		{"level", desired.Level},
+		{"start", desired.Start},
+		{"duration", desired.Duration},
+		{"users", stringsValue(&desired.Users)},
+	}
+	if desired.Frequency != "" {
+		fields = append(fields, configField{"frequency", desired.Frequency})
+	}
+	if desired.Interval != 0 {
+		fields = append(fields, configField{"interval", desired.Interval})
+	}
+	if desired.WeekStart != "" {
+		fields = append(fields, configField{"week_start", desired.WeekStart})
+	}
+	if len(desired.ByDay) > 0 {
+		fields = append(fields, configField{"by_day", desired.ByDay})
+	}
+	if len(desired.ByMonth) > 0 {
+		fields = append(fields, configField{"by_month", desired.ByMonth})
+	}
+	if len(desired.ByMonthday) > 0 {
+		fields = append(fields, configField{"by_monthday", desired.ByMonthday})
+	}
+	if len(desired.RollingUsers) > 0 {
+		fields = append(fields, configField{"rolling_users", desired.RollingUsers})
+	}
+	return fields
+}
+
+func shiftFields(live *OnCallShift) []configField {
+	fields := []configField{
+		{"type", live.Type},
+		{"level", live.Level},
+		{"start", live.Start},
+		{"duration", live.Duration},
+		{"users", stringsValue(live.Users)},
+		{"frequency", stringValue(live.Frequency)},
+		{"interval", intValue(live.Interval)},
+		{"week_start", stringValue(live.WeekStart)},
+	}
+	var byDay []string
+	if live.ByDay != nil && len(*live.ByDay) > 0 {
+		byDay = *live.ByDay
+	}
+	var byMonth, byMonthday []int
+	if live.ByMonth != nil && len(*live.ByMonth) > 0 {
+		byMonth = *live.ByMonth
+	}
+	if live.ByMonthday != nil && len(*live.ByMonthday) > 0 {
+		byMonthday = *live.ByMonthday
+	}
+	var rollingUsers [][]string
+	if live.RollingUsers != nil && len(*live.RollingUsers) > 0 {
+		rollingUsers = *live.RollingUsers
+	}
+	return append(fields,
+		configField{"by_day", byDay},
+		configField{"by_month", byMonth},
+		configField{"by_monthday", byMonthday},
+		configField{"rolling_users", rollingUsers},
+	)
+}
+
+// mergeShift returns update of live shift with managed fields of desired one
+func mergeShift(desired *OnCallShiftConfig, live *OnCallShift) *UpdateOnCallShiftOptions {
+	level := desired.Level
+	users := append([]string{}, desired.Users...)
+	opt := &UpdateOnCallShiftOptions{
+		Type:         desired.Type,
+		Name:         desired.Name,
+		Level:        &level,
+		Start:        desired.Start,
+		Duration:     desired.Duration,
+		Frequency:    live.Frequency,
+		Users:        &users,
+		Interval:     live.Interval,
+		WeekStart:    live.WeekStart,
+		ByDay:        live.ByDay,
+		ByMonth:      live.ByMonth,
+		ByMonthday:   live.ByMonthday,
+		RollingUsers: live.RollingUsers,
+	}
+	if desired.Frequency != "" {
+		opt.Frequency = &desired.Frequency
+	}
+	if desired.Interval != 0 {
+		opt.Interval = &desired.Interval
+	}
+	if desired.WeekStart != "" {
+		opt.WeekStart = &desired.WeekStart
+	}
+	if len(desired.ByDay) > 0 {
+		opt.ByDay = &desired.ByDay
+	}
+	if len(desired.ByMonth) > 0 {
+		opt.ByMonth = &desired.ByMonth
+	}
+	if len(desired.ByMonthday) > 0 {
+		opt.ByMonthday = &desired.ByMonthday
+	}
+	if len(desired.RollingUsers) > 0 {
+		opt.RollingUsers = &desired.RollingUsers
+	}
+	return opt
+}
+
+// mergeTemplates returns live templates with managed templates of desired ones
+func mergeTemplates(desired *TemplatesConfig, live *Templates) *Templates {
+	if desired == nil {
+		return live
+	}
+	merged := &Templates{}
+	if live != nil {
+		*merged = *live
+	}
+	if desired.GroupingKey != nil {
+		merged.GroupingKey = desired.GroupingKey
+	}
+	if desired.ResolveSignal != nil {
+		merged.ResolveSignal = desired.ResolveSignal
+	}
+	if desired.Slack != nil {
+		slack := &SlackTemplate{}
+		if merged.Slack != nil {
+			*slack = *merged.Slack
+		}
+		if desired.Slack.Title != nil {
+			slack.Title = desired.Slack.Title
+		}
+		if desired.Slack.Message != nil {
+			slack.Message = desired.Slack.Message
+		}
+		if desired.Slack.ImageURL != nil {
+			slack.ImageURL = desired.Slack.ImageURL
+		}
+		merged.Slack = slack
+	}
+	return merged
+}
+
+func routeSlack(channel string, live *SlackRoute) *SlackRoute {
+	if channel == "" {
+		return live
+	}
+	return &SlackRoute{ChannelId: &channel}
+}
+
+func scheduleSlack(channel string, live *SlackSchedule) *SlackSchedule {
+	if channel == "" {
+		return live
+	}
+	return &SlackSchedule{ChannelId: &channel}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// stringsValue returns nil for nil or empty slice
+func stringsValue(s *[]string) []string {
+	if s == nil || len(*s) == 0 {
+		return nil
+	}
+	return *s
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalStrings(s []string) *[]string {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s
+}
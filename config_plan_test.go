@@ -0,0 +1,608 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testConfigAPI is in-memory API of configuration objects serving ConfigService requests
+type testConfigAPI struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]map[string]interface{}
+	nextID  int
+	// writes are non GET requests like "POST routes"
+	writes []string
+}
+
+// testConfigFilters are object fields by query parameters filtering collections
+var testConfigFilters = map[string]map[string]string{
+	"routes":              {"integration_id": "integration_id"},
+	"escalation_policies": {"route_id": "route_id", "escalation_chain_id": "escalation_chain_id"},
+	"on_call_shifts":      {"schedule_id": "schedule_id"},
+	"users":               {"email": "email"},
+	"slack_channels":      {"channel_name": "name"},
+	"escalation_chains":   {"name": "name"},
+	"schedules":           {"name": "name"},
+	"actions":             {"name": "name"},
+}
+
+func setupConfigAPI(t *testing.T, objects map[string]string) (*testConfigAPI, *Client, func()) {
+	mux, server, client := setup(t)
+	api := &testConfigAPI{t: t, objects: make(map[string][]map[string]interface{})}
+	for collection, body := range objects {
+		var items []map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &items); err != nil {
+			t.Fatal(err)
+		}
+		api.objects[collection] = items
+	}
+	mux.HandleFunc("/api/v1/", api.serve)
+	return api, client, func() { teardown(server) }
+}
+
+func (api *testConfigAPI) serve(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/"), "/")
+	collection := parts[0]
+	if r.Method != "GET" {
+		api.writes = append(api.writes, strings.TrimSpace(r.Method+" "+collection+" "+strings.Join(parts[1:], "")))
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case "GET":
+			results := []map[string]interface{}{}
+		objects:
+			for _, object := range api.objects[collection] {
+				for param, field := range testConfigFilters[collection] {
+					if value := r.URL.Query().Get(param); value != "" && object[field] != value {
+						continue objects
+					}
+				}
+				results = append(results, object)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "previous": nil, "results": results})
+		case "POST":
+			object := api.decode(r)
+			api.nextID++
+			object["id"] = fmt.Sprintf("NEW%d", api.nextID)
+			switch collection {
+			case "integrations":
+				route := map[string]interface{}{"id": object["id"].(string) + "DEFAULT", "integration_id": object["id"], "position": 0, "is_the_last_route": true, "routing_regex": ""}
+				api.objects["routes"] = append(api.objects["routes"], route)
+				object["default_route_id"] = route["id"]
+			case "schedules":
+				object["type"] = ScheduleTypeCalendar
+				if object["ical_url"] != nil {
+					object["type"] = ScheduleTypeICal
+				}
+			}
+			api.objects[collection] = append(api.objects[collection], object)
+			json.NewEncoder(w).Encode(object)
+		default:
+			api.t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		return
+	}
+
+	for i, object := range api.objects[collection] {
+		if object["id"] != parts[1] {
+			continue
+		}
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(object)
+		case "PUT":
+			for key, value := range api.decode(r) {
+				object[key] = value
+			}
+			json.NewEncoder(w).Encode(object)
+		case "DELETE":
+			api.objects[collection] = append(api.objects[collection][:i], api.objects[collection][i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, `{"detail": "Not found."}`)
+}
+
+func (api *testConfigAPI) decode(r *http.Request) map[string]interface{} {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(data, &object); err != nil {
+		api.t.Fatalf("invalid request body %s: %v", data, err)
+	}
+	return object
+}
+
+func (api *testConfigAPI) object(collection, id string) map[string]interface{} {
+	for _, object := range api.objects[collection] {
+		if object["id"] == id {
+			return object
+		}
+	}
+	return nil
+}
+
+var testConfigLive = map[string]string{
+	"schedules": `[
+		{"id": "SP1", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []},
+		{"id": "SL1", "type": "calendar", "name": "Legacy", "time_zone": "UTC", "on_call_now": []}
+	]`,
+	"on_call_shifts": `[
+		{"id": "SH1", "schedule_id": "SP1", "type": "recurrent_event", "name": "Days", "level": 0, "start": "2020-09-04T09:00:00",
+			"duration": 28800, "frequency": "daily", "interval": 1, "users": ["U1"]},
+		{"id": "SH2", "schedule_id": "SP1", "type": "single_event", "name": "Old", "level": 0, "start": "2020-09-04T09:00:00",
+			"duration": 3600, "users": ["U1"]},
+		{"id": "SH3", "schedule_id": "SL1", "type": "single_event", "name": "Legacy", "level": 0, "start": "2020-09-04T09:00:00",
+			"duration": 3600, "users": ["U2"]}
+	]`,
+	"integrations": `[
+		{"id": "I1", "name": "Grafana", "type": "grafana", "default_route_id": "RD1",
+			"templates": {"grouping_key": null, "resolve_signal": null, "slack": {"title": "old", "message": null, "image_url": null}}}
+	]`,
+	"routes": `[
+		{"id": "R1", "integration_id": "I1", "position": 0, "routing_regex": "us-west", "is_the_last_route": false},
+		{"id": "R2", "integration_id": "I1", "position": 1, "routing_regex": "eu", "is_the_last_route": false},
+		{"id": "RD1", "integration_id": "I1", "position": 2, "routing_regex": "", "is_the_last_route": true}
+	]`,
+	"escalation_policies": `[
+		{"id": "ED1", "route_id": "RD1", "position": 0, "type": "notify_persons", "persons_to_notify": ["U1"]},
+		{"id": "E1", "route_id": "R1", "position": 0, "type": "wait", "duration": 300},
+		{"id": "E2", "route_id": "R1", "position": 1, "type": "notify_persons", "persons_to_notify": ["U1"]},
+		{"id": "E3", "route_id": "R1", "position": 2, "type": "resolve"},
+		{"id": "E4", "route_id": "R2", "position": 0, "type": "resolve"}
+	]`,
+}
+
+func testConfig() *Config {
+	title := "new"
+	return &Config{
+		Schedules: []*ScheduleConfig{
+			{
+				Name:     "Primary",
+				TimeZone: "UTC",
+				Shifts: []*OnCallShiftConfig{
+					{Name: "Days", Type: OnCallShiftTypeRecurrentEvent, Start: "2020-09-04T09:00:00", Duration: 36000, Frequency: FrequencyDaily, Users: []string{"U1"}},
+					{Name: "Nights", Type: OnCallShiftTypeRecurrentEvent, Start: "2020-09-04T19:00:00", Duration: 50400, Frequency: FrequencyDaily, Users: []string{"U2"}},
+				},
+			},
+			{Name: "Secondary", TimeZone: "Europe/Berlin"},
+		},
+		Integrations: []*IntegrationConfig{
+			{
+				Name:      "Grafana",
+				Type:      "grafana",
+				Templates: &TemplatesConfig{Slack: &SlackTemplateConfig{Title: &title}},
+				DefaultRoute: &RouteConfig{Escalations: []*EscalationConfig{
+					{Type: EscalationTypeNotifyPersons, PersonsToNotify: []string{"U1"}},
+				}},
+				Routes: []*RouteConfig{
+					{RoutingRegex: "us-east", Escalations: []*EscalationConfig{
+						{Type: EscalationTypeNotifyOnCallFromSchedule, Schedule: "Secondary"},
+					}},
+					{RoutingRegex: "us-west", Escalations: []*EscalationConfig{
+						{Type: EscalationTypeWait, Duration: 300},
+						{Type: EscalationTypeNotifyPersons, PersonsToNotify: []string{"U1", "U2"}, Important: true},
+					}},
+				},
+			},
+			{
+				Name: "Alertmanager",
+				Type: "alertmanager",
+				DefaultRoute: &RouteConfig{Escalations: []*EscalationConfig{
+					{Type: EscalationTypeNotifyOnCallFromSchedule, Schedule: "Primary"},
+				}},
+				Routes: []*RouteConfig{{RoutingRegex: "critical", SlackChannel: "C1"}},
+			},
+		},
+	}
+}
+
+func TestConfigPlan(t *testing.T) {
+	api, client, done := setupConfigAPI(t, testConfigLive)
+	defer done()
+
+	plan, err := client.Config.Plan(testConfig(), &PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `- delete schedule "Primary" shift "Old" (SH2)
+- delete integration "Grafana" route "us-west" escalation 2 (E3)
+- delete integration "Grafana" route "eu" escalation 0 (E4)
+- delete integration "Grafana" route "eu" (R2)
+~ update schedule "Primary" shift "Days" (SH1)
+    duration: 28800 -> 36000
++ create schedule "Primary" shift "Nights"
+    type: "recurrent_event"
+    start: "2020-09-04T19:00:00"
+    duration: 50400
+    users: ["U2"]
+    frequency: "daily"
++ create schedule "Secondary"
+    time_zone: "Europe/Berlin"
+~ update integration "Grafana" (I1)
+    templates.slack.title: "old" -> "new"
++ create integration "Alertmanager"
+    type: "alertmanager"
++ create integration "Grafana" route "us-east"
+    routing_regex: "us-east"
++ create integration "Grafana" route "us-east" escalation 0
+    type: "notify_on_call_from_schedule"
+    notify_on_call_from_schedule: "Secondary"
+~ update integration "Grafana" route "us-west" (R1)
+    position: 0 -> 1
+~ update integration "Grafana" route "us-west" escalation 1 (E2)
+    persons_to_notify: ["U1"] -> ["U1","U2"]
+    important: false -> true
++ create integration "Alertmanager" default route escalation 0
+    type: "notify_on_call_from_schedule"
+    notify_on_call_from_schedule: "SP1"
++ create integration "Alertmanager" route "critical"
+    routing_regex: "critical"
+    slack.channel_id: "C1"
+- delete schedule "Legacy" shift "Legacy" (SH3)
+- delete schedule "Legacy" (SL1)
+7 to create, 4 to update, 6 to delete
+`
+	if got := plan.String(); got != want {
+		t.Errorf("plan is\n%s\nwant\n%s", got, want)
+	}
+	if len(api.writes) != 0 {
+		t.Errorf("planning wrote %v", api.writes)
+	}
+
+	if err := client.Config.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	wantWrites := []string{
+		"DELETE on_call_shifts SH2",
+		"DELETE escalation_policies E3",
+		"DELETE escalation_policies E4",
+		"DELETE routes R2",
+		"PUT on_call_shifts SH1",
+		"POST on_call_shifts",
+		"POST schedules",
+		"PUT integrations I1",
+		"POST integrations",
+		"POST routes",
+		"POST escalation_policies",
+		"PUT routes R1",
+		"PUT escalation_policies E2",
+		"POST escalation_policies",
+		"POST routes",
+		"DELETE on_call_shifts SH3",
+		"DELETE schedules SL1",
+	}
+	if !reflect.DeepEqual(wantWrites, api.writes) {
+		t.Errorf("writes are\n %s\nwant\n %s", strings.Join(api.writes, "\n "), strings.Join(wantWrites, "\n "))
+	}
+
+	// created ids are passed to children
+	for id, want := range map[string]map[string]interface{}{
+		"NEW5": {"route_id": "NEW4", "notify_on_call_from_schedule": "NEW2"},
+		"NEW6": {"route_id": "NEW3DEFAULT", "notify_on_call_from_schedule": "SP1"},
+	} {
+		escalation := api.object("escalation_policies", id)
+		for key, value := range want {
+			if escalation[key] != value {
+				t.Errorf("escalation %s %s is %v, want %v", id, key, escalation[key], value)
+			}
+		}
+	}
+	// positions are written with manual order, so the server doesn't shift them
+	for collection, id := range map[string]string{"routes": "R1", "escalation_policies": "NEW5"} {
+		if object := api.object(collection, id); object["manual_order"] != true {
+			t.Errorf("%s %s is written without manual order: %v", collection, id, object)
+		}
+	}
+	if title := api.object("integrations", "I1")["templates"].(map[string]interface{})["slack"].(map[string]interface{})["title"]; title != "new" {
+		t.Errorf("slack title is %v", title)
+	}
+
+	api.writes = nil
+	plan, err = client.Config.Plan(testConfig(), &PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("plan after apply is not empty\n%s", plan)
+	}
+	if err := client.Config.Apply(plan); err != nil || len(api.writes) != 0 {
+		t.Errorf("applying empty plan wrote %v, error %v", api.writes, err)
+	}
+}
+
+func TestConfigPlanWithoutPrune(t *testing.T) {
+	_, client, done := setupConfigAPI(t, testConfigLive)
+	defer done()
+
+	plan, err := client.Config.Plan(&Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() || plan.String() != "No changes\n" {
+		t.Errorf("plan of empty config is\n%s", plan)
+	}
+
+	plan, err = client.Config.Plan(&Config{}, &PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deletes []string
+	for _, change := range plan.Changes {
+		if change.Action != ChangeDelete {
+			t.Errorf("unexpected %s %s", change.Action, change.Path)
+		}
+		deletes = append(deletes, change.ID)
+	}
+	// children before parents, the default route is deleted with its integration
+	want := []string{"E1", "E2", "E3", "R1", "E4", "R2", "ED1", "I1", "SH1", "SH2", "SP1", "SH3", "SL1"}
+	if !reflect.DeepEqual(want, deletes) {
+		t.Errorf("deletes are %v, want %v", deletes, want)
+	}
+}
+
+func TestConfigPlanEscalationChains(t *testing.T) {
+	api, client, done := setupConfigAPI(t, map[string]string{
+		"escalation_chains": `[{"id": "EC1", "name": "Europe"}, {"id": "EC2", "name": "Old"}]`,
+		"escalation_policies": `[
+			{"id": "E1", "escalation_chain_id": "EC1", "position": 0, "type": "wait", "duration": 300},
+			{"id": "E2", "escalation_chain_id": "EC1", "position": 1, "type": "resolve"},
+			{"id": "E3", "escalation_chain_id": "EC2", "position": 0, "type": "resolve"}
+		]`,
+	})
+	defer done()
+
+	config := &Config{
+		Schedules: []*ScheduleConfig{{Name: "Secondary"}},
+		EscalationChains: []*EscalationChainConfig{
+			{Name: "Europe", Escalations: []*EscalationConfig{{Type: EscalationTypeWait, Duration: 600}}},
+			{Name: "Asia", Escalations: []*EscalationConfig{{Type: EscalationTypeNotifyOnCallFromSchedule, Schedule: "Secondary"}}},
+		},
+		Integrations: []*IntegrationConfig{{
+			Name: "Grafana",
+			Type: "grafana",
+			Routes: []*RouteConfig{
+				{RoutingRegex: "asia", EscalationChain: "Asia"},
+				{RoutingRegex: "eu", EscalationChain: "Europe"},
+				{RoutingRegex: "us", EscalationChain: "EC9"},
+			},
+		}},
+	}
+	plan, err := client.Config.Plan(config, &PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `- delete escalation chain "Europe" escalation 1 (E2)
++ create schedule "Secondary"
++ create integration "Grafana"
+    type: "grafana"
+~ update escalation chain "Europe" escalation 0 (E1)
+    duration: 300 -> 600
++ create escalation chain "Asia"
++ create escalation chain "Asia" escalation 0
+    type: "notify_on_call_from_schedule"
+    notify_on_call_from_schedule: "Secondary"
++ create integration "Grafana" route "asia"
+    routing_regex: "asia"
+    escalation_chain_id: "Asia"
++ create integration "Grafana" route "eu"
+    routing_regex: "eu"
+    position: 1
+    escalation_chain_id: "EC1"
++ create integration "Grafana" route "us"
+    routing_regex: "us"
+    position: 2
+    escalation_chain_id: "EC9"
+- delete escalation chain "Old" escalation 0 (E3)
+- delete escalation chain "Old" (EC2)
+7 to create, 1 to update, 3 to delete
+`
+	if got := plan.String(); got != want {
+		t.Errorf("plan is\n%s\nwant\n%s", got, want)
+	}
+
+	if err := client.Config.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	// created ids are passed to chain escalations and routes
+	for _, check := range []struct {
+		collection, id, field string
+		want                  interface{}
+	}{
+		{"escalation_policies", "NEW4", "escalation_chain_id", "NEW3"},
+		{"escalation_policies", "NEW4", "notify_on_call_from_schedule", "NEW1"},
+		{"routes", "NEW5", "escalation_chain_id", "NEW3"},
+		{"routes", "NEW6", "escalation_chain_id", "EC1"},
+	} {
+		if got := api.object(check.collection, check.id)[check.field]; got != check.want {
+			t.Errorf("%s %s %s is %v, want %v", check.collection, check.id, check.field, got, check.want)
+		}
+	}
+	if api.object("escalation_policies", "NEW4")["route_id"] != nil {
+		t.Error("chain escalation is created with route")
+	}
+
+	plan, err = client.Config.Plan(config, &PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("plan after apply is not empty\n%s", plan)
+	}
+}
+
+func TestConfigPlanCustomActions(t *testing.T) {
+	api, client, done := setupConfigAPI(t, testExportLive)
+	defer done()
+
+	config := &Config{CustomActions: []*CustomActionConfig{
+		{Name: "Restart", Integration: "I2", Url: "https://example.com/restart", HttpMethod: "PUT"},
+		{Name: "Page", Integration: "I1", Url: "https://example.com/page"},
+	}}
+	plan, err := client.Config.Plan(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `~ update custom action "Restart" (A1)
+    http_method: "POST" -> "PUT"
++ create custom action "Page"
+    integration_id: "I1"
+    url: "https://example.com/page"
+1 to create, 1 to update, 0 to delete
+`
+	if got := plan.String(); got != want {
+		t.Errorf("plan is\n%s\nwant\n%s", got, want)
+	}
+
+	if err := client.Config.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	// data missing in the config isn't managed, so it is kept
+	if action := api.object("actions", "A1"); action["http_method"] != "PUT" || action["integration_id"] != "I2" || action["data"] != "{}" {
+		t.Errorf("updated custom action is %v", action)
+	}
+	if action := api.object("actions", "NEW1"); action["name"] != "Page" || action["integration_id"] != "I1" {
+		t.Errorf("created custom action is %v", action)
+	}
+
+	config.CustomActions[0].Integration = "I1"
+	if _, err := client.Config.Plan(config, nil); err == nil ||
+		err.Error() != `integration_id of custom action "Restart" can't be changed from "I2" to "I1"` {
+		t.Errorf("error is %v", err)
+	}
+}
+
+func TestConfigPlanErrors(t *testing.T) {
+	_, client, done := setupConfigAPI(t, testConfigLive)
+	defer done()
+
+	tests := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{
+			name:   "integration type",
+			config: &Config{Integrations: []*IntegrationConfig{{Name: "Grafana", Type: "webhook"}}},
+			err:    `type of integration "Grafana" can't be changed from "grafana" to "webhook"`,
+		},
+		{
+			name:   "schedule type",
+			config: &Config{Schedules: []*ScheduleConfig{{Name: "Primary", ICalUrl: "https://example.com/on-call.ics"}}},
+			err:    `type of schedule "Primary" can't be changed from "calendar" to "ical"`,
+		},
+		{
+			name:   "invalid config",
+			config: &Config{Schedules: []*ScheduleConfig{{Name: "Primary"}, {Name: "Primary"}}},
+			err:    `duplicate schedule "Primary"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Config.Plan(tt.config, nil)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("error is %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConfigPlanDuplicates(t *testing.T) {
+	managed := &Config{
+		Integrations: []*IntegrationConfig{{Name: "Grafana", Type: "grafana"}},
+		Schedules:    []*ScheduleConfig{{Name: "Primary"}},
+	}
+	tests := []struct {
+		name string
+		live map[string]string
+		// config is empty by default, so duplicates would be pruned
+		config *Config
+		err    string
+	}{
+		{
+			name: "schedules",
+			live: map[string]string{"schedules": `[{"id": "S1", "type": "calendar", "name": "Primary"}, {"id": "S2", "type": "calendar", "name": "Primary"}]`},
+			err:  `duplicate live schedule "Primary": S1 and S2`,
+		},
+		{
+			name: "integrations",
+			live: map[string]string{"integrations": `[{"id": "I1", "name": "Grafana", "type": "grafana"}, {"id": "I2", "name": "Grafana", "type": "webhook"}]`},
+			err:  `duplicate live integration "Grafana": I1 and I2`,
+		},
+		{
+			name: "custom actions",
+			live: map[string]string{"actions": `[{"id": "A1", "name": "Restart"}, {"id": "A2", "name": "Restart"}]`},
+			err:  `duplicate live custom action "Restart": A1 and A2`,
+		},
+		{
+			name: "escalation chains",
+			live: map[string]string{"escalation_chains": `[{"id": "EC1", "name": "Europe"}, {"id": "EC2", "name": "Europe"}]`},
+			err:  `duplicate live escalation chain "Europe": EC1 and EC2`,
+		},
+		{
+			name: "shifts",
+			live: map[string]string{
+				"schedules":      `[{"id": "S1", "type": "calendar", "name": "Primary"}]`,
+				"on_call_shifts": `[{"id": "SH1", "schedule_id": "S1", "name": "Days"}, {"id": "SH2", "schedule_id": "S1", "name": "Days"}]`,
+			},
+			config: managed,
+			err:    `duplicate live schedule "Primary" shift "Days": SH1 and SH2`,
+		},
+		{
+			name: "routes",
+			live: map[string]string{
+				"integrations": `[{"id": "I1", "name": "Grafana", "type": "grafana"}]`,
+				"routes": `[{"id": "R1", "integration_id": "I1", "routing_regex": "eu"}, {"id": "R2", "integration_id": "I1", "routing_regex": "eu"},
+					{"id": "RD1", "integration_id": "I1", "routing_regex": "", "is_the_last_route": true}]`,
+			},
+			config: managed,
+			err:    `duplicate live integration "Grafana" route "eu": R1 and R2`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client, done := setupConfigAPI(t, tt.live)
+			defer done()
+
+			config := tt.config
+			if config == nil {
+				config = &Config{}
+			}
+			_, err := client.Config.Plan(config, &PlanOptions{Prune: true})
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("error is %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPlanJSON(t *testing.T) {
+	plan := &Plan{Changes: []*Change{
+		{Action: ChangeUpdate, Kind: ConfigKindRoute, Path: `integration "Grafana" route "us-west"`, ID: "R1",
+			Diffs: []*FieldDiff{{Field: "position", Old: "0", New: "1"}}},
+	}}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"changes":[{"action":"update","kind":"route","path":"integration \"Grafana\" route \"us-west\"","id":"R1",` +
+		`"diffs":[{"field":"position","old":"0","new":"1"}]}]}`
+	if string(data) != want {
+		t.Errorf("returned\n %s\nwant\n %s", data, want)
+	}
+}
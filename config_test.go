@@ -0,0 +1,125 @@
+package amixr
+
+import (
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	shift := func(name, start string) *OnCallShiftConfig {
+		return &OnCallShiftConfig{Name: name, Type: OnCallShiftTypeSingleEvent, Start: start, Duration: 3600}
+	}
+	tests := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{name: "valid", config: testConfig()},
+		{name: "empty", config: &Config{}},
+		{
+			name:   "integration without name",
+			config: &Config{Integrations: []*IntegrationConfig{{Type: "grafana"}}},
+			err:    "integration name required",
+		},
+		{
+			name:   "duplicate integration",
+			config: &Config{Integrations: []*IntegrationConfig{{Name: "Grafana", Type: "grafana"}, {Name: "Grafana", Type: "webhook"}}},
+			err:    `duplicate integration "Grafana"`,
+		},
+		{
+			name:   "integration without type",
+			config: &Config{Integrations: []*IntegrationConfig{{Name: "Grafana"}}},
+			err:    `type of integration "Grafana" required`,
+		},
+		{
+			name: "default route with routing regex",
+			config: &Config{Integrations: []*IntegrationConfig{
+				{Name: "Grafana", Type: "grafana", DefaultRoute: &RouteConfig{RoutingRegex: ".*"}},
+			}},
+			err: `default route of integration "Grafana" can't have routing regex`,
+		},
+		{
+			name: "route without routing regex",
+			config: &Config{Integrations: []*IntegrationConfig{
+				{Name: "Grafana", Type: "grafana", Routes: []*RouteConfig{{}}},
+			}},
+			err: `routing regex of integration "Grafana" route required`,
+		},
+		{
+			name: "duplicate route",
+			config: &Config{Integrations: []*IntegrationConfig{
+				{Name: "Grafana", Type: "grafana", Routes: []*RouteConfig{{RoutingRegex: "eu"}, {RoutingRegex: "eu"}}},
+			}},
+			err: `duplicate route "eu" of integration "Grafana"`,
+		},
+		{
+			name: "escalation without type",
+			config: &Config{Integrations: []*IntegrationConfig{
+				{Name: "Grafana", Type: "grafana", Routes: []*RouteConfig{
+					{RoutingRegex: "eu", Escalations: []*EscalationConfig{{Type: EscalationTypeWait, Duration: 60}, {}}},
+				}},
+			}},
+			err: `route "eu" of integration "Grafana": type of escalation 1 required`,
+		},
+		{
+			name:   "schedule without name",
+			config: &Config{Schedules: []*ScheduleConfig{{TimeZone: "UTC"}}},
+			err:    "schedule name required",
+		},
+		{
+			name: "ical schedule with shifts",
+			config: &Config{Schedules: []*ScheduleConfig{
+				{Name: "External", ICalUrl: "https://example.com/on-call.ics", Shifts: []*OnCallShiftConfig{shift("Days", "2020-09-04T09:00:00")}},
+			}},
+			err: `ical schedule "External" can't have shifts`,
+		},
+		{
+			name: "duplicate shift",
+			config: &Config{Schedules: []*ScheduleConfig{
+				{Name: "Primary", Shifts: []*OnCallShiftConfig{shift("Days", "2020-09-04T09:00:00"), shift("Days", "2020-09-05T09:00:00")}},
+			}},
+			err: `duplicate shift "Days" of schedule "Primary"`,
+		},
+		{
+			name: "shift with invalid start",
+			config: &Config{Schedules: []*ScheduleConfig{
+				{Name: "Primary", Shifts: []*OnCallShiftConfig{shift("Days", "2020-09-04 09:00")}},
+			}},
+			err: `invalid start "2020-09-04 09:00" of schedule "Primary" shift "Days"`,
+		},
+		{
+			name:   "duplicate escalation chain",
+			config: &Config{EscalationChains: []*EscalationChainConfig{{Name: "Europe"}, {Name: "Europe"}}},
+			err:    `duplicate escalation chain "Europe"`,
+		},
+		{
+			name: "escalation chain escalation without type",
+			config: &Config{EscalationChains: []*EscalationChainConfig{
+				{Name: "Europe", Escalations: []*EscalationConfig{{}}},
+			}},
+			err: `escalation chain "Europe": type of escalation 0 required`,
+		},
+		{
+			name: "duplicate custom action",
+			config: &Config{CustomActions: []*CustomActionConfig{
+				{Name: "Restart", Url: "https://example.com"}, {Name: "Restart", Url: "https://example.com"},
+			}},
+			err: `duplicate custom action "Restart"`,
+		},
+		{
+			name:   "custom action without url",
+			config: &Config{CustomActions: []*CustomActionConfig{{Name: "Restart"}}},
+			err:    `url of custom action "Restart" required`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("error is %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 )
 
+// Handles requests to custom action (outgoing webhook) endpoint
+// Use NewCustomActionService instead of direct creation CustomActionService
 type CustomActionService struct {
 	client *Client
 	url    string
@@ -24,9 +26,17 @@ type PaginatedCustomActionsResponse struct {
 }
 
 type CustomAction struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	IntegrationId string `json:"integration_id"`
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	IntegrationId       string  `json:"integration_id"`
+	Url                 string  `json:"url"`
+	HttpMethod          string  `json:"http_method"`
+	Headers             *string `json:"headers"`
+	Data                *string `json:"data"`
+	User                *string `json:"user"`
+	Password            *string `json:"password"`
+	AuthorizationHeader *string `json:"authorization_header"`
+	ForwardWholePayload bool    `json:"forward_whole_payload"`
 }
 
 type ListCustomActionOptions struct {
@@ -36,10 +46,10 @@ type ListCustomActionOptions struct {
 }
 
 // ListCustomActions gets all customActions for authorized team
-func (service *CustomActionService) ListCustomActions(opt *ListCustomActionOptions) (*PaginatedCustomActionsResponse, *http.Response, error) {
+func (service *CustomActionService) ListCustomActions(opt *ListCustomActionOptions, options ...RequestOption) (*PaginatedCustomActionsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -52,3 +62,146 @@ func (service *CustomActionService) ListCustomActions(opt *ListCustomActionOptio
 
 	return customActions, resp, err
 }
+
+// CustomActionIterator iterates over custom actions of all pages.
+// Use CustomActionService.Iter to create one.
+type CustomActionIterator struct {
+	pager
+	items []*CustomAction
+}
+
+// Iter returns iterator over all custom actions matching opt, following pagination links
+func (service *CustomActionService) Iter(opt *ListCustomActionOptions, options ...RequestOption) *CustomActionIterator {
+	u := fmt.Sprintf("%s", service.url)
+	it := &CustomActionIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedCustomActionsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.CustomActions
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current customAction
+func (it *CustomActionIterator) Value() *CustomAction {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllCustomActions gets custom actions of all pages
+func (service *CustomActionService) ListAllCustomActions(opt *ListCustomActionOptions, options ...RequestOption) ([]*CustomAction, error) {
+	var customActions []*CustomAction
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		customActions = append(customActions, it.Value())
+	}
+	return customActions, it.Err()
+}
+
+type GetCustomActionOptions struct {
+}
+
+// Get custom action by given id
+func (service *CustomActionService) GetCustomAction(id string, opt *GetCustomActionOptions, options ...RequestOption) (*CustomAction, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	customAction := new(CustomAction)
+	resp, err := service.client.Do(req, customAction)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return customAction, resp, err
+}
+
+type CreateCustomActionOptions struct {
+	Name                string  `json:"name"`
+	IntegrationId       string  `json:"integration_id,omitempty"`
+	Url                 string  `json:"url"`
+	HttpMethod          string  `json:"http_method,omitempty"`
+	Headers             *string `json:"headers,omitempty"`
+	Data                *string `json:"data,omitempty"`
+	User                *string `json:"user,omitempty"`
+	Password            *string `json:"password,omitempty"`
+	AuthorizationHeader *string `json:"authorization_header,omitempty"`
+	ForwardWholePayload bool    `json:"forward_whole_payload"`
+}
+
+// Create custom action
+func (service *CustomActionService) CreateCustomAction(opt *CreateCustomActionOptions, options ...RequestOption) (*CustomAction, *http.Response, error) {
+	service.client.logger.Debug("create amixr custom action")
+	u := fmt.Sprintf("%s/", service.url)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	customAction := new(CustomAction)
+
+	resp, err := service.client.Do(req, customAction)
+
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return customAction, resp, err
+}
+
+type UpdateCustomActionOptions struct {
+	Name       string `json:"name,omitempty"`
+	Url        string `json:"url,omitempty"`
+	HttpMethod string `json:"http_method,omitempty"`
+	// Headers and Data are always sent, nil removes them from the action
+	Headers             *string `json:"headers"`
+	Data                *string `json:"data"`
+	User                *string `json:"user,omitempty"`
+	Password            *string `json:"password,omitempty"`
+	AuthorizationHeader *string `json:"authorization_header,omitempty"`
+	ForwardWholePayload *bool   `json:"forward_whole_payload,omitempty"`
+}
+
+// Updates custom action
+func (service *CustomActionService) UpdateCustomAction(id string, opt *UpdateCustomActionOptions, options ...RequestOption) (*CustomAction, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	customAction := new(CustomAction)
+	resp, err := service.client.Do(req, customAction)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return customAction, resp, err
+}
+
+type DeleteCustomActionOptions struct {
+}
+
+// Deletes custom action
+func (service *CustomActionService) DeleteCustomAction(id string, opt *DeleteCustomActionOptions, options ...RequestOption) (*http.Response, error) {
+
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
@@ -1,22 +1,41 @@
 package amixr
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
 )
 
+var testCustomActionData = `{"alert_payload": {{ alert_payload }}}`
+var testCustomActionUser = "bot"
+var testCustomActionPassword = "secret"
+
 var testCustomAction = &CustomAction{
-	ID:            "KGEFG74LU1D8L",
-	Name:          "Test action",
-	IntegrationId: "CGEXJ922S7TXQ",
+	ID:                  "KGEFG74LU1D8L",
+	Name:                "Test action",
+	IntegrationId:       "CGEXJ922S7TXQ",
+	Url:                 "https://example.com/hook",
+	HttpMethod:          "POST",
+	Data:                &testCustomActionData,
+	User:                &testCustomActionUser,
+	Password:            &testCustomActionPassword,
+	ForwardWholePayload: false,
 }
 
 var testCustomActionBody = `{
 	"id": "KGEFG74LU1D8L",
 	"name": "Test action",
-	"integration_id": "CGEXJ922S7TXQ"
+	"integration_id": "CGEXJ922S7TXQ",
+	"url": "https://example.com/hook",
+	"http_method": "POST",
+	"headers": null,
+	"data": "{\"alert_payload\": {{ alert_payload }}}",
+	"user": "bot",
+	"password": "secret",
+	"authorization_header": null,
+	"forward_whole_payload": false
 }`
 
 func TestListCustomActions(t *testing.T) {
@@ -51,3 +70,116 @@ func TestListCustomActions(t *testing.T) {
 		t.Errorf("returned\n %+v, \nwant\n %+v", customActions, want)
 	}
 }
+
+func TestCreateCustomAction(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/actions/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["url"] != "https://example.com/hook" || body["forward_whole_payload"] != false {
+			t.Errorf("unexpected body %+v", body)
+		}
+		if _, ok := body["headers"]; ok {
+			t.Errorf("headers should be omitted, got %+v", body)
+		}
+		fmt.Fprint(w, testCustomActionBody)
+	})
+
+	createOptions := &CreateCustomActionOptions{
+		Name:          "Test action",
+		IntegrationId: "CGEXJ922S7TXQ",
+		Url:           "https://example.com/hook",
+		HttpMethod:    "POST",
+		Data:          &testCustomActionData,
+		User:          &testCustomActionUser,
+		Password:      &testCustomActionPassword,
+	}
+	customAction, _, err := client.CustomActions.CreateCustomAction(createOptions)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testCustomAction
+
+	if !reflect.DeepEqual(want, customAction) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", customAction, want)
+	}
+}
+
+func TestGetCustomAction(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/actions/KGEFG74LU1D8L/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testCustomActionBody)
+	})
+
+	customAction, _, err := client.CustomActions.GetCustomAction("KGEFG74LU1D8L", &GetCustomActionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testCustomAction
+
+	if !reflect.DeepEqual(want, customAction) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", customAction, want)
+	}
+}
+
+func TestUpdateCustomAction(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/actions/KGEFG74LU1D8L/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "PUT")
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		// nil headers and data remove them, credentials are kept
+		if headers, ok := body["headers"]; !ok || headers != nil {
+			t.Errorf("headers aren't removed, got %+v", body)
+		}
+		if _, ok := body["password"]; ok {
+			t.Errorf("password should be omitted, got %+v", body)
+		}
+		fmt.Fprint(w, testCustomActionBody)
+	})
+
+	forward := false
+	updateOptions := &UpdateCustomActionOptions{
+		Url:                 "https://example.com/hook",
+		ForwardWholePayload: &forward,
+	}
+	customAction, _, err := client.CustomActions.UpdateCustomAction("KGEFG74LU1D8L", updateOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testCustomAction
+
+	if !reflect.DeepEqual(want, customAction) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", customAction, want)
+	}
+}
+
+func TestDeleteCustomAction(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/actions/KGEFG74LU1D8L/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "DELETE")
+	})
+
+	_, err := client.CustomActions.DeleteCustomAction("KGEFG74LU1D8L", &DeleteCustomActionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
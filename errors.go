@@ -0,0 +1,106 @@
+package amixr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by API errors with corresponding status code,
+// e.g. errors.Is(err, amixr.ErrNotFound)
+var (
+	ErrUnauthorized = errors.New("amixr: unauthorized")
+	ErrForbidden    = errors.New("amixr: forbidden")
+	ErrNotFound     = errors.New("amixr: not found")
+	ErrRateLimited  = errors.New("amixr: rate limited")
+)
+
+// nonFieldErrorsKey holds validation errors not bound to a particular field
+const nonFieldErrorsKey = "non_field_errors"
+
+// Is reports whether the error matches one of the sentinel errors by status code.
+func (e *ErrorResponse) Is(target error) bool {
+	if e.Response == nil {
+		return false
+	}
+	switch target {
+	case ErrUnauthorized:
+		return e.Response.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Response.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.Response.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Response.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// ValidationError is returned when API rejects request data.
+// Use errors.As to get per-field messages.
+type ValidationError struct {
+	*ErrorResponse
+	// Fields maps field name to its messages. Nested fields are joined with dots,
+	// e.g. "slack.channel_id". Messages not bound to a field are stored under "non_field_errors".
+	Fields map[string][]string
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// ServerError is returned when API fails with 5xx status, after retries are exhausted.
+type ServerError struct {
+	*ErrorResponse
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// parseFieldErrors converts decoded error body into field messages.
+// Returns nil if body has no recognizable structure.
+func parseFieldErrors(raw interface{}) map[string][]string {
+	fields := make(map[string][]string)
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		for k, v := range raw {
+			collectFieldErrors(k, v, fields)
+		}
+	case []interface{}, string:
+		collectFieldErrors(nonFieldErrorsKey, raw, fields)
+	default:
+		return nil
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+func collectFieldErrors(field string, raw interface{}, fields map[string][]string) {
+	switch raw := raw.(type) {
+	case string:
+		fields[field] = append(fields[field], raw)
+
+	case []interface{}:
+		for i, v := range raw {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				collectFieldErrors(fmt.Sprintf("%s[%d]", field, i), v, fields)
+			default:
+				collectFieldErrors(field, v, fields)
+			}
+		}
+
+	case map[string]interface{}:
+		for k, v := range raw {
+			collectFieldErrors(field+"."+k, v, fields)
+		}
+
+	case nil:
+
+	default:
+		fields[field] = append(fields[field], fmt.Sprint(raw))
+	}
+}
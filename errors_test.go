@@ -0,0 +1,149 @@
+package amixr
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestResponse(t *testing.T, status int, body string) *http.Response {
+	c, err := NewClient("token")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req, err := c.NewRequest("GET", "test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	return &http.Response{
+		Request:    req.Request,
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestCheckResponseSentinels(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrRateLimited}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusConflict, nil},
+	}
+
+	for _, tt := range tests {
+		err := CheckResponse(newTestResponse(t, tt.status, `{"detail": "error"}`))
+		for _, sentinel := range sentinels {
+			if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+				t.Errorf("status %d: errors.Is(%v) is %v", tt.status, sentinel, got)
+			}
+		}
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("status %d: expected *ErrorResponse, got %T", tt.status, err)
+		}
+		if string(errResp.Body) != `{"detail": "error"}` {
+			t.Errorf("status %d: unexpected body %q", tt.status, errResp.Body)
+		}
+	}
+}
+
+func TestCheckResponseValidationError(t *testing.T) {
+	body := `{
+		"name": ["This field is required."],
+		"slack": {"channel_id": ["Invalid channel."]},
+		"by_day": [{}, {"day": "Unknown day."}],
+		"non_field_errors": "Shift overlaps."
+	}`
+	err := CheckResponse(newTestResponse(t, http.StatusBadRequest, body))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError, got %T", err)
+	}
+
+	want := map[string][]string{
+		"name":             {"This field is required."},
+		"slack.channel_id": {"Invalid channel."},
+		"by_day[1].day":    {"Unknown day."},
+		"non_field_errors": {"Shift overlaps."},
+	}
+	if !reflect.DeepEqual(want, validationErr.Fields) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", validationErr.Fields, want)
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected wrapped *ErrorResponse with status 400")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("validation error should not match ErrNotFound")
+	}
+}
+
+func TestCheckResponseValidationErrorList(t *testing.T) {
+	err := CheckResponse(newTestResponse(t, http.StatusBadRequest, `["Invalid input."]`))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError, got %T", err)
+	}
+	want := map[string][]string{"non_field_errors": {"Invalid input."}}
+	if !reflect.DeepEqual(want, validationErr.Fields) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", validationErr.Fields, want)
+	}
+}
+
+func TestCheckResponseServerError(t *testing.T) {
+	err := CheckResponse(newTestResponse(t, http.StatusBadGateway, `<html>Bad Gateway</html>`))
+
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("expected *ServerError, got %T", err)
+	}
+	if serverErr.Response.StatusCode != http.StatusBadGateway {
+		t.Errorf("status is %d, want %d", serverErr.Response.StatusCode, http.StatusBadGateway)
+	}
+	if serverErr.Message != "failed to parse unknown error format" {
+		t.Errorf("unexpected message %q", serverErr.Message)
+	}
+}
+
+func TestDoReturnsTypedErrorAfterRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"detail": "Request was throttled."}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(1, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, resp, err := client.Integrations.GetIntegration("CFRPV98RPR1U8", &GetIntegrationOptions{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected response with status 429, got %+v", resp)
+	}
+	if calls != 2 {
+		t.Errorf("server called %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,204 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Handles requests to escalation chain endpoint
+// Escalation chain is a named list of escalation steps which can be shared by several routes.
+// Use NewEscalationChainService instead of direct creation EscalationChainService
+type EscalationChainService struct {
+	client *Client
+	url    string
+}
+
+// NewEscalationChainService creates EscalationChainService with defined url
+func NewEscalationChainService(client *Client) *EscalationChainService {
+	escalationChainService := EscalationChainService{}
+	escalationChainService.client = client
+	escalationChainService.url = "escalation_chains"
+	return &escalationChainService
+}
+
+type PaginatedEscalationChainsResponse struct {
+	PaginatedResponse
+	EscalationChains []*EscalationChain `json:"results"`
+}
+
+type EscalationChain struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	TeamId *string `json:"team_id"`
+}
+
+type ListEscalationChainOptions struct {
+	ListOptions
+	Name string `url:"name,omitempty" json:"name,omitempty"`
+}
+
+// ListEscalationChains gets all escalation chains for authorized team
+func (service *EscalationChainService) ListEscalationChains(opt *ListEscalationChainOptions, options ...RequestOption) (*PaginatedEscalationChainsResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var escalationChains *PaginatedEscalationChainsResponse
+	resp, err := service.client.Do(req, &escalationChains)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return escalationChains, resp, err
+}
+
+// EscalationChainIterator iterates over escalation chains of all pages.
+// Use EscalationChainService.Iter to create one.
+type EscalationChainIterator struct {
+	pager
+	items []*EscalationChain
+}
+
+// Iter returns iterator over all escalation chains matching opt, following pagination links
+func (service *EscalationChainService) Iter(opt *ListEscalationChainOptions, options ...RequestOption) *EscalationChainIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &EscalationChainIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedEscalationChainsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.EscalationChains
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current escalationChain
+func (it *EscalationChainIterator) Value() *EscalationChain {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllEscalationChains gets escalation chains of all pages
+func (service *EscalationChainService) ListAllEscalationChains(opt *ListEscalationChainOptions, options ...RequestOption) ([]*EscalationChain, error) {
+	var escalationChains []*EscalationChain
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		escalationChains = append(escalationChains, it.Value())
+	}
+	return escalationChains, it.Err()
+}
+
+type GetEscalationChainOptions struct {
+}
+
+// Get escalation chain by given id
+func (service *EscalationChainService) GetEscalationChain(id string, opt *GetEscalationChainOptions, options ...RequestOption) (*EscalationChain, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	escalationChain := new(EscalationChain)
+	resp, err := service.client.Do(req, escalationChain)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return escalationChain, resp, err
+}
+
+type CreateEscalationChainOptions struct {
+	Name   string  `json:"name"`
+	TeamId *string `json:"team_id,omitempty"`
+}
+
+// Create escalation chain with given name
+func (service *EscalationChainService) CreateEscalationChain(opt *CreateEscalationChainOptions, options ...RequestOption) (*EscalationChain, *http.Response, error) {
+	service.client.logger.Debug("create amixr escalation chain")
+	u := fmt.Sprintf("%s/", service.url)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	escalationChain := new(EscalationChain)
+
+	resp, err := service.client.Do(req, escalationChain)
+
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return escalationChain, resp, err
+}
+
+type UpdateEscalationChainOptions struct {
+	Name string `json:"name"`
+}
+
+// Updates escalation chain
+func (service *EscalationChainService) UpdateEscalationChain(id string, opt *UpdateEscalationChainOptions, options ...RequestOption) (*EscalationChain, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	escalationChain := new(EscalationChain)
+	resp, err := service.client.Do(req, escalationChain)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return escalationChain, resp, err
+}
+
+type CopyEscalationChainOptions struct {
+	Name string `json:"name"`
+}
+
+// Copies escalation chain with all its escalation steps under a new name
+func (service *EscalationChainService) CopyEscalationChain(id string, opt *CopyEscalationChainOptions, options ...RequestOption) (*EscalationChain, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/copy/", service.url, id)
+
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	escalationChain := new(EscalationChain)
+	resp, err := service.client.Do(req, escalationChain)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return escalationChain, resp, err
+}
+
+type DeleteEscalationChainOptions struct {
+}
+
+// Deletes escalation chain
+func (service *EscalationChainService) DeleteEscalationChain(id string, opt *DeleteEscalationChainOptions, options ...RequestOption) (*http.Response, error) {
+
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
@@ -0,0 +1,158 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testEscalationChain = &EscalationChain{
+	ID:   "F5JU6KJET33FE",
+	Name: "Default",
+}
+
+var testEscalationChainBody = `{
+	"id": "F5JU6KJET33FE",
+	"name": "Default",
+	"team_id": null
+}`
+
+func TestCreateEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		fmt.Fprint(w, testEscalationChainBody)
+	})
+
+	escalationChain, _, err := client.EscalationChains.CreateEscalationChain(&CreateEscalationChainOptions{Name: "Default"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testEscalationChain
+
+	if !reflect.DeepEqual(want, escalationChain) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", escalationChain, want)
+	}
+}
+
+func TestListEscalationChains(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.URL.Query().Get("name"); got != "Default" {
+			t.Errorf("name is %q, want %q", got, "Default")
+		}
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testEscalationChainBody))
+	})
+
+	escalationChains, _, err := client.EscalationChains.ListEscalationChains(&ListEscalationChainOptions{Name: "Default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PaginatedEscalationChainsResponse{
+		PaginatedResponse: PaginatedResponse{
+			Count:    1,
+			Next:     nil,
+			Previous: nil,
+		},
+		EscalationChains: []*EscalationChain{
+			testEscalationChain,
+		},
+	}
+	if !reflect.DeepEqual(want, escalationChains) {
+		t.Errorf("returned\n %+v, \nwant\n %+v", escalationChains, want)
+	}
+}
+
+func TestGetEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/F5JU6KJET33FE/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testEscalationChainBody)
+	})
+
+	escalationChain, _, err := client.EscalationChains.GetEscalationChain("F5JU6KJET33FE", &GetEscalationChainOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testEscalationChain
+
+	if !reflect.DeepEqual(want, escalationChain) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", escalationChain, want)
+	}
+}
+
+func TestUpdateEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/F5JU6KJET33FE/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "PUT")
+		fmt.Fprint(w, testEscalationChainBody)
+	})
+
+	escalationChain, _, err := client.EscalationChains.UpdateEscalationChain("F5JU6KJET33FE", &UpdateEscalationChainOptions{Name: "Default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testEscalationChain
+
+	if !reflect.DeepEqual(want, escalationChain) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", escalationChain, want)
+	}
+}
+
+func TestCopyEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/F5JU6KJET33FE/copy/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["name"] != "Default copy" {
+			t.Errorf("name is %q, want %q", body["name"], "Default copy")
+		}
+		fmt.Fprint(w, `{"id": "F3KDRU6KX3XEE", "name": "Default copy", "team_id": null}`)
+	})
+
+	escalationChain, _, err := client.EscalationChains.CopyEscalationChain("F5JU6KJET33FE", &CopyEscalationChainOptions{Name: "Default copy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &EscalationChain{ID: "F3KDRU6KX3XEE", Name: "Default copy"}
+
+	if !reflect.DeepEqual(want, escalationChain) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", escalationChain, want)
+	}
+}
+
+func TestDeleteEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/escalation_chains/F5JU6KJET33FE/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "DELETE")
+	})
+
+	_, err := client.EscalationChains.DeleteEscalationChain("F5JU6KJET33FE", &DeleteEscalationChainOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,7 +2,6 @@ package amixr
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 )
 
@@ -23,6 +22,20 @@ func NewEscalationService(client *Client) *EscalationService {
 	return &escalationService
 }
 
+// Escalation types
+const (
+	EscalationTypeWait                     = "wait"
+	EscalationTypeNotifyPersons            = "notify_persons"
+	EscalationTypeNotifyPersonNextEachTime = "notify_person_next_each_time"
+	EscalationTypeNotifyOnCallFromSchedule = "notify_on_call_from_schedule"
+	EscalationTypeNotifyUserGroup          = "notify_user_group"
+	EscalationTypeNotifyWholeChannel       = "notify_whole_channel"
+	EscalationTypeTriggerAction            = "trigger_action"
+	EscalationTypeNotifyIfTimeFromTo       = "notify_if_time_from_to"
+	EscalationTypeRepeatEscalation         = "repeat_escalation"
+	EscalationTypeResolve                  = "resolve"
+)
+
 type PaginatedEscalationsResponse struct {
 	PaginatedResponse
 	Escalations []*Escalation `json:"results"`
@@ -31,6 +44,7 @@ type PaginatedEscalationsResponse struct {
 type Escalation struct {
 	ID                       string    `json:"id"`
 	RouteId                  string    `json:"route_id"`
+	EscalationChainId        *string   `json:"escalation_chain_id"`
 	Position                 int       `json:"position"`
 	Type                     *string   `json:"type"`
 	Duration                 *int      `json:"duration"`
@@ -44,18 +58,19 @@ type Escalation struct {
 	NotifyIfTimeTo           *string   `json:"notify_if_time_to"`
 }
 
-// Empty struct is here in case we want to add request params to ListEscalations.
 type ListEscalationOptions struct {
 	ListOptions
+	RouteId           string `url:"route_id,omitempty" json:"route_id,omitempty"`
+	EscalationChainId string `url:"escalation_chain_id,omitempty" json:"escalation_chain_id,omitempty"`
 }
 
 // ListEscalations gets all escalations for authorized team
 //
 // http://api-docs.amixr.io/#list-escalations
-func (service *EscalationService) ListEscalations(opt *ListEscalationOptions) (*PaginatedEscalationsResponse, *http.Response, error) {
+func (service *EscalationService) ListEscalations(opt *ListEscalationOptions, options ...RequestOption) (*PaginatedEscalationsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -69,16 +84,57 @@ func (service *EscalationService) ListEscalations(opt *ListEscalationOptions) (*
 	return escalations, resp, err
 }
 
+// EscalationIterator iterates over escalations of all pages.
+// Use EscalationService.Iter to create one.
+type EscalationIterator struct {
+	pager
+	items []*Escalation
+}
+
+// Iter returns iterator over all escalations matching opt, following pagination links
+func (service *EscalationService) Iter(opt *ListEscalationOptions, options ...RequestOption) *EscalationIterator {
+	u := fmt.Sprintf("%s", service.url)
+	it := &EscalationIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedEscalationsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Escalations
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current escalation
+func (it *EscalationIterator) Value() *Escalation {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllEscalations gets escalations of all pages
+func (service *EscalationService) ListAllEscalations(opt *ListEscalationOptions, options ...RequestOption) ([]*Escalation, error) {
+	var escalations []*Escalation
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		escalations = append(escalations, it.Value())
+	}
+	return escalations, it.Err()
+}
+
 type GetEscalationOptions struct {
 }
 
 // Get escalation by given id
 //
 // http://api-docs.amixr.io/#get-escalation
-func (service *EscalationService) GetEscalation(id string, opt *GetEscalationOptions) (*Escalation, *http.Response, error) {
+func (service *EscalationService) GetEscalation(id string, opt *GetEscalationOptions, options ...RequestOption) (*Escalation, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -94,6 +150,7 @@ func (service *EscalationService) GetEscalation(id string, opt *GetEscalationOpt
 
 type CreateEscalationOptions struct {
 	RouteId                     string    `json:"route_id,omitempty"`
+	EscalationChainId           string    `json:"escalation_chain_id,omitempty"`
 	Position                    *int      `json:"position,omitempty"`
 	Type                        *string   `json:"type"`
 	Duration                    int       `json:"duration,omitempty"`
@@ -111,10 +168,10 @@ type CreateEscalationOptions struct {
 // Create escalation with given name and type
 //
 // http://api-docs.amixr.io/#create-escalation
-func (service *EscalationService) CreateEscalation(opt *CreateEscalationOptions) (*Escalation, *http.Response, error) {
-	log.Printf("[DEBUG] create amixr escalation")
+func (service *EscalationService) CreateEscalation(opt *CreateEscalationOptions, options ...RequestOption) (*Escalation, *http.Response, error) {
+	service.client.logger.Debug("create amixr escalation")
 	u := fmt.Sprintf("%s/", service.url)
-	req, err := service.client.NewRequest("POST", u, opt)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -122,8 +179,6 @@ func (service *EscalationService) CreateEscalation(opt *CreateEscalationOptions)
 	escalation := new(Escalation)
 
 	resp, err := service.client.Do(req, escalation)
-	log.Printf("[DEBUG] request success")
-
 	if err != nil {
 		return nil, resp, err
 	}
@@ -149,10 +204,10 @@ type UpdateEscalationOptions struct {
 // Updates escalation with new templates and/or name. At least one field in template is required
 //
 // http://api-docs.amixr.io/#update-escalation
-func (service *EscalationService) UpdateEscalation(id string, opt *UpdateEscalationOptions) (*Escalation, *http.Response, error) {
+func (service *EscalationService) UpdateEscalation(id string, opt *UpdateEscalationOptions, options ...RequestOption) (*Escalation, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("PUT", u, opt)
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -172,11 +227,11 @@ type DeleteEscalationOptions struct {
 // Deletes escalation
 //
 // http://api-docs.amixr.io/#delete-escalation
-func (service *EscalationService) DeleteEscalation(id string, opt *DeleteEscalationOptions) (*http.Response, error) {
+func (service *EscalationService) DeleteEscalation(id string, opt *DeleteEscalationOptions, options ...RequestOption) (*http.Response, error) {
 
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("DELETE", u, opt)
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
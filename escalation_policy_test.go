@@ -10,25 +10,29 @@ import (
 var duration = 60
 var typeWait = "wait"
 var typeNotifyPersons = "notify_persons"
+var testEscalationChainId = "F5JU6KJET33FE"
 
 var testEscalation = &Escalation{
-	ID:       "E3GA6SJETWWJS",
-	RouteId:  "RIYGUJXCPFHXY",
-	Position: 0,
-	Type:     &typeWait,
-	Duration: &duration,
+	ID:                "E3GA6SJETWWJS",
+	RouteId:           "RIYGUJXCPFHXY",
+	EscalationChainId: &testEscalationChainId,
+	Position:          0,
+	Type:              &typeWait,
+	Duration:          &duration,
 }
 
 var testEscalationEmptyDuration = &Escalation{
-	ID:       "E3GA6SJETWWJS",
-	RouteId:  "RIYGUJXCPFHXY",
-	Position: 0,
-	Type:     &typeNotifyPersons,
+	ID:                "E3GA6SJETWWJS",
+	RouteId:           "RIYGUJXCPFHXY",
+	EscalationChainId: &testEscalationChainId,
+	Position:          0,
+	Type:              &typeNotifyPersons,
 }
 
 var testEscalationBody = `{
 	"id": "E3GA6SJETWWJS",
     "route_id": "RIYGUJXCPFHXY",
+    "escalation_chain_id": "F5JU6KJET33FE",
     "position": 0,
     "type": "wait",
     "duration": 60
@@ -37,6 +41,7 @@ var testEscalationBody = `{
 var testEscalationEmptyDurationBody = `{
 	"id": "E3GA6SJETWWJS",
     "route_id": "RIYGUJXCPFHXY",
+    "escalation_chain_id": "F5JU6KJET33FE",
     "position": 0,
     "type": "notify_persons"
 }`
@@ -44,6 +49,7 @@ var testEscalationEmptyDurationBody = `{
 var testUpdatedEscalationBody = `{
 	"id": "E3GA6SJETWWJS",
     "route_id": "RIYGUJXCPFHXY",
+    "escalation_chain_id": "F5JU6KJET33FE",
     "position": 1,
     "type": "wait",
     "duration": 60
@@ -194,11 +200,12 @@ func TestUpdateEscalation(t *testing.T) {
 	}
 	var duration = 60
 	var testUpdatedEscalation = &Escalation{
-		ID:       "E3GA6SJETWWJS",
-		RouteId:  "RIYGUJXCPFHXY",
-		Position: 1,
-		Type:     &typeWait,
-		Duration: &duration,
+		ID:                "E3GA6SJETWWJS",
+		RouteId:           "RIYGUJXCPFHXY",
+		EscalationChainId: &testEscalationChainId,
+		Position:          1,
+		Type:              &typeWait,
+		Duration:          &duration,
 	}
 
 	want := testUpdatedEscalation
@@ -0,0 +1,257 @@
+package amixr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxEscalationRepeats is how many times repeat_escalation step restarts escalation
+const MaxEscalationRepeats = 5
+
+// EscalationSimulationOptions configures escalation simulation
+type EscalationSimulationOptions struct {
+	// Start is the time alert fires
+	Start time.Time
+	// Schedules are on-call sources by schedule id for notify_on_call_from_schedule steps.
+	// EscalationService.SimulateEscalation gets schedules missing here.
+	Schedules map[string]OnCallSource
+	// NextEachTime are indices of persons notified next by notify_person_next_each_time steps,
+	// by escalation id, the first person by default
+	NextEachTime map[string]int
+}
+
+// EscalationEvent is a single escalation step taken
+type EscalationEvent struct {
+	Time       time.Time   `json:"time"`
+	Escalation *Escalation `json:"escalation"`
+	// Repeat is number of times escalation was repeated before the step
+	Repeat int    `json:"repeat"`
+	Type   string `json:"type"`
+	// Users are ids of notified users
+	Users        []string `json:"users,omitempty"`
+	Schedule     string   `json:"schedule,omitempty"`
+	UserGroup    string   `json:"user_group,omitempty"`
+	CustomAction string   `json:"custom_action,omitempty"`
+	Important    bool     `json:"important,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// EscalationTimeline is the result of escalation simulation
+type EscalationTimeline struct {
+	Start time.Time `json:"start"`
+	// End is the time the last step was taken
+	End    time.Time          `json:"end"`
+	Events []*EscalationEvent `json:"events"`
+	// Resolved is true when resolve step resolved the alert
+	Resolved bool `json:"resolved"`
+	// Stopped is true when notify_if_time_from_to step stopped escalation
+	Stopped bool `json:"stopped"`
+}
+
+// SimulateEscalation walks escalation steps ordered by position as if the alert fired at opt.Start
+// and nobody acknowledged it. Notify_if_time_from_to step stops escalation outside its UTC time range,
+// repeat_escalation step restarts it up to MaxEscalationRepeats times and notify_person_next_each_time
+// step notifies the next person each time it is taken.
+func SimulateEscalation(escalations []*Escalation, opt *EscalationSimulationOptions) (*EscalationTimeline, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("escalation simulation options required")
+	}
+	steps := make([]*Escalation, len(escalations))
+	copy(steps, escalations)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Position < steps[j].Position })
+
+	nextEachTime := make(map[string]int)
+	for id, index := range opt.NextEachTime {
+		nextEachTime[id] = index
+	}
+
+	timeline := &EscalationTimeline{Start: opt.Start, Events: []*EscalationEvent{}}
+	at := opt.Start
+	repeats := 0
+	for i := 0; i < len(steps); i++ {
+		step := steps[i]
+		event := &EscalationEvent{Time: at, Escalation: step, Repeat: repeats}
+		if step.Type != nil {
+			event.Type = *step.Type
+		}
+		important := step.Important != nil && *step.Important
+
+		switch event.Type {
+		case EscalationTypeWait:
+			var d time.Duration
+			if step.Duration != nil {
+				d = time.Duration(*step.Duration) * time.Second
+			}
+			event.Message = fmt.Sprintf("wait %s", d)
+			at = at.Add(d)
+		case EscalationTypeNotifyPersons:
+			if step.PersonsToNotify != nil {
+				event.Users = append([]string{}, *step.PersonsToNotify...)
+			}
+			event.Important = important
+			event.Message = notifyMessage(event.Users, "no persons to notify")
+		case EscalationTypeNotifyPersonNextEachTime:
+			if step.PersonsToNotifyEachTime != nil && len(*step.PersonsToNotifyEachTime) > 0 {
+				persons := *step.PersonsToNotifyEachTime
+				index := nextEachTime[step.ID] % len(persons)
+				event.Users = []string{persons[index]}
+				nextEachTime[step.ID] = index + 1
+			}
+			event.Important = important
+			event.Message = notifyMessage(event.Users, "no persons to notify")
+		case EscalationTypeNotifyOnCallFromSchedule:
+			if step.NotifyOnCallFromSchedule == nil {
+				event.Message = "no schedule to notify"
+				break
+			}
+			event.Schedule = *step.NotifyOnCallFromSchedule
+			source, ok := opt.Schedules[event.Schedule]
+			if !ok {
+				return nil, fmt.Errorf("on-call source of schedule %s is not given", event.Schedule)
+			}
+			users, err := whoIsOnCall(source, at)
+			if err != nil {
+				return nil, err
+			}
+			event.Users = users
+			event.Important = important
+			event.Message = notifyMessage(users, fmt.Sprintf("nobody is on call in schedule %s", event.Schedule))
+		case EscalationTypeNotifyUserGroup:
+			if step.GroupToNotify != nil {
+				event.UserGroup = *step.GroupToNotify
+			}
+			event.Important = important
+			event.Message = fmt.Sprintf("notify user group %s", event.UserGroup)
+		case EscalationTypeNotifyWholeChannel:
+			event.Message = "notify whole channel"
+		case EscalationTypeTriggerAction:
+			if step.ActionToTrigger != nil {
+				event.CustomAction = *step.ActionToTrigger
+			}
+			event.Message = fmt.Sprintf("trigger custom action %s", event.CustomAction)
+		case EscalationTypeNotifyIfTimeFromTo:
+			in, err := inEscalationTimeRange(step, at)
+			if err != nil {
+				return nil, err
+			}
+			if !in {
+				event.Message = "escalation stopped out of time range"
+				timeline.Events = append(timeline.Events, event)
+				timeline.Stopped = true
+				timeline.End = at
+				return timeline, nil
+			}
+			event.Message = "continue escalation within time range"
+		case EscalationTypeRepeatEscalation:
+			if repeats < MaxEscalationRepeats {
+				repeats++
+				i = -1
+				event.Message = fmt.Sprintf("repeat escalation, %d of %d", repeats, MaxEscalationRepeats)
+			} else {
+				event.Message = "repeat limit reached"
+			}
+		case EscalationTypeResolve:
+			event.Message = "resolve alert group"
+			timeline.Events = append(timeline.Events, event)
+			timeline.Resolved = true
+			timeline.End = at
+			return timeline, nil
+		default:
+			event.Message = fmt.Sprintf("unknown escalation type %q skipped", event.Type)
+		}
+		timeline.Events = append(timeline.Events, event)
+	}
+	timeline.End = at
+	return timeline, nil
+}
+
+// SimulateEscalation gets escalations of the route, or of its escalation chain when it has one,
+// with schedules they notify and simulates escalation, see SimulateEscalation
+func (service *EscalationService) SimulateEscalation(routeID string, opt *EscalationSimulationOptions, options ...RequestOption) (*EscalationTimeline, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("escalation simulation options required")
+	}
+	route, _, err := service.client.Routes.GetRoute(routeID, &GetRouteOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	list := &ListEscalationOptions{RouteId: routeID}
+	if chainID := stringValue(route.EscalationChainId); chainID != "" {
+		list = &ListEscalationOptions{EscalationChainId: chainID}
+	}
+	escalations, err := service.ListAllEscalations(list, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	simulate := *opt
+	simulate.Schedules = make(map[string]OnCallSource)
+	for id, source := range opt.Schedules {
+		simulate.Schedules[id] = source
+	}
+	var users []*User
+	for _, escalation := range escalations {
+		if escalation.Type == nil || *escalation.Type != EscalationTypeNotifyOnCallFromSchedule || escalation.NotifyOnCallFromSchedule == nil {
+			continue
+		}
+		id := *escalation.NotifyOnCallFromSchedule
+		if _, ok := simulate.Schedules[id]; ok {
+			continue
+		}
+		schedule, _, err := service.client.Schedules.GetSchedule(id, &GetScheduleOptions{}, options...)
+		if err != nil {
+			return nil, err
+		}
+		if schedule.Type == ScheduleTypeICal && users == nil {
+			if users, err = service.client.Users.ListAllUsers(&ListUserOptions{}, options...); err != nil {
+				return nil, err
+			}
+		}
+		source, err := service.client.Schedules.onCallSource(schedule, users, options)
+		if err != nil {
+			return nil, err
+		}
+		simulate.Schedules[id] = source
+	}
+	return SimulateEscalation(escalations, &simulate)
+}
+
+func notifyMessage(users []string, empty string) string {
+	if len(users) == 0 {
+		return empty
+	}
+	return "notify " + strings.Join(users, ", ")
+}
+
+// inEscalationTimeRange reports whether time of day of t in UTC is within the inclusive range of the step,
+// which may wrap over midnight
+func inEscalationTimeRange(step *Escalation, t time.Time) (bool, error) {
+	if step.NotifyIfTimeFrom == nil || step.NotifyIfTimeTo == nil {
+		return false, fmt.Errorf("escalation %s has no time range", step.ID)
+	}
+	from, err := parseEscalationTime(*step.NotifyIfTimeFrom)
+	if err != nil {
+		return false, err
+	}
+	to, err := parseEscalationTime(*step.NotifyIfTimeTo)
+	if err != nil {
+		return false, err
+	}
+	utc := t.UTC()
+	now := utc.Sub(time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC))
+	if from <= to {
+		return now >= from && now <= to, nil
+	}
+	return now >= from || now <= to, nil
+}
+
+// parseEscalationTime parses UTC time of day like "09:30:00Z"
+func parseEscalationTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04:05", strings.TrimSuffix(s, "Z"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid escalation time %q", s)
+	}
+	return t.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)), nil
+}
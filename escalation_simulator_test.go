@@ -0,0 +1,245 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEscalationStep(id string, position int, escalationType string) *Escalation {
+	return &Escalation{ID: id, RouteId: "RIYGUJXCPFHXY", Position: position, Type: &escalationType}
+}
+
+func testEscalationSteps() []*Escalation {
+	important := true
+	wait5, wait10 := 300, 600
+	schedule, group, action := "SBM7DV7BKFUYU", "GPFAPH7J7BKJB", "KGEFG74LU1D8L"
+	from, to := "09:00:00Z", "17:00:00Z"
+
+	notify := testEscalationStep("NOTIFY", 0, EscalationTypeNotifyPersons)
+	notify.PersonsToNotify = &[]string{"A"}
+	notify.Important = &important
+	firstWait := testEscalationStep("WAIT5", 1, EscalationTypeWait)
+	firstWait.Duration = &wait5
+	each := testEscalationStep("EACH", 2, EscalationTypeNotifyPersonNextEachTime)
+	each.PersonsToNotifyEachTime = &[]string{"B", "C", "D"}
+	onCall := testEscalationStep("ONCALL", 3, EscalationTypeNotifyOnCallFromSchedule)
+	onCall.NotifyOnCallFromSchedule = &schedule
+	secondWait := testEscalationStep("WAIT10", 4, EscalationTypeWait)
+	secondWait.Duration = &wait10
+	timeRange := testEscalationStep("TIME", 5, EscalationTypeNotifyIfTimeFromTo)
+	timeRange.NotifyIfTimeFrom, timeRange.NotifyIfTimeTo = &from, &to
+	userGroup := testEscalationStep("GROUP", 6, EscalationTypeNotifyUserGroup)
+	userGroup.GroupToNotify = &group
+	trigger := testEscalationStep("ACTION", 7, EscalationTypeTriggerAction)
+	trigger.ActionToTrigger = &action
+	repeat := testEscalationStep("REPEAT", 8, EscalationTypeRepeatEscalation)
+
+	// out of order to check ordering by position
+	return []*Escalation{repeat, trigger, userGroup, timeRange, secondWait, onCall, each, firstWait, notify}
+}
+
+func formatEscalationEvents(events []*EscalationEvent) []string {
+	var result []string
+	for _, e := range events {
+		s := fmt.Sprintf("%s r%d %s: %s", e.Time.UTC().Format("15:04"), e.Repeat, e.Escalation.ID, e.Message)
+		if e.Important {
+			s += " (important)"
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestSimulateEscalation(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedules := map[string]OnCallSource{"SBM7DV7BKFUYU": calculator}
+
+	t.Run("stopped out of time range", func(t *testing.T) {
+		timeline, err := SimulateEscalation(testEscalationSteps(), &EscalationSimulationOptions{
+			Start:        time.Date(2020, 9, 7, 20, 50, 0, 0, time.UTC),
+			Schedules:    schedules,
+			NextEachTime: map[string]int{"EACH": 2},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{
+			"20:50 r0 NOTIFY: notify A (important)",
+			"20:50 r0 WAIT5: wait 5m0s",
+			"20:55 r0 EACH: notify D",
+			"20:55 r0 ONCALL: notify A",
+			"20:55 r0 WAIT10: wait 10m0s",
+			"21:05 r0 TIME: escalation stopped out of time range",
+		}
+		if got := formatEscalationEvents(timeline.Events); !reflect.DeepEqual(want, got) {
+			t.Errorf("events are\n %s\nwant\n %s", strings.Join(got, "\n "), strings.Join(want, "\n "))
+		}
+		if !timeline.Stopped || timeline.Resolved || !timeline.End.Equal(time.Date(2020, 9, 7, 21, 5, 0, 0, time.UTC)) {
+			t.Errorf("stopped %t resolved %t end %s", timeline.Stopped, timeline.Resolved, timeline.End)
+		}
+	})
+
+	t.Run("repeated", func(t *testing.T) {
+		timeline, err := SimulateEscalation(testEscalationSteps(), &EscalationSimulationOptions{
+			Start:     time.Date(2020, 9, 7, 8, 50, 0, 0, time.UTC),
+			Schedules: schedules,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := formatEscalationEvents(timeline.Events)
+		if len(got) != 9*(MaxEscalationRepeats+1) {
+			t.Fatalf("%d events, want %d", len(got), 9*(MaxEscalationRepeats+1))
+		}
+		want := []string{
+			"08:50 r0 NOTIFY: notify A (important)",
+			"08:50 r0 WAIT5: wait 5m0s",
+			"08:55 r0 EACH: notify B",
+			"08:55 r0 ONCALL: nobody is on call in schedule SBM7DV7BKFUYU",
+			"08:55 r0 WAIT10: wait 10m0s",
+			"09:05 r0 TIME: continue escalation within time range",
+			"09:05 r0 GROUP: notify user group GPFAPH7J7BKJB",
+			"09:05 r0 ACTION: trigger custom action KGEFG74LU1D8L",
+			"09:05 r0 REPEAT: repeat escalation, 1 of 5",
+			"09:05 r1 NOTIFY: notify A (important)",
+			"09:05 r1 WAIT5: wait 5m0s",
+			"09:10 r1 EACH: notify C",
+			"09:10 r1 ONCALL: notify A",
+		}
+		if !reflect.DeepEqual(want, got[:len(want)]) {
+			t.Errorf("events are\n %s\nwant\n %s", strings.Join(got[:len(want)], "\n "), strings.Join(want, "\n "))
+		}
+		var each []string
+		for _, event := range timeline.Events {
+			if event.Escalation.ID == "EACH" {
+				each = append(each, event.Users...)
+			}
+		}
+		if want := []string{"B", "C", "D", "B", "C", "D"}; !reflect.DeepEqual(want, each) {
+			t.Errorf("persons notified each time are %v, want %v", each, want)
+		}
+		if last := got[len(got)-1]; last != "10:20 r5 REPEAT: repeat limit reached" {
+			t.Errorf("last event is %q", last)
+		}
+		if timeline.Stopped || timeline.Resolved || !timeline.End.Equal(time.Date(2020, 9, 7, 10, 20, 0, 0, time.UTC)) {
+			t.Errorf("stopped %t resolved %t end %s", timeline.Stopped, timeline.Resolved, timeline.End)
+		}
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		wait := 60
+		steps := []*Escalation{
+			testEscalationStep("WAIT", 0, EscalationTypeWait),
+			testEscalationStep("UNKNOWN", 1, "notify_by_pigeon"),
+			testEscalationStep("RESOLVE", 2, EscalationTypeResolve),
+			testEscalationStep("NOTIFY", 3, EscalationTypeNotifyPersons),
+		}
+		steps[0].Duration = &wait
+		timeline, err := SimulateEscalation(steps, &EscalationSimulationOptions{Start: time.Date(2020, 9, 7, 12, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{
+			"12:00 r0 WAIT: wait 1m0s",
+			`12:01 r0 UNKNOWN: unknown escalation type "notify_by_pigeon" skipped`,
+			"12:01 r0 RESOLVE: resolve alert group",
+		}
+		if got := formatEscalationEvents(timeline.Events); !reflect.DeepEqual(want, got) {
+			t.Errorf("events are\n %s\nwant\n %s", strings.Join(got, "\n "), strings.Join(want, "\n "))
+		}
+		if !timeline.Resolved {
+			t.Error("expected resolved timeline")
+		}
+	})
+
+	if _, err := SimulateEscalation(testEscalationSteps(), &EscalationSimulationOptions{Start: time.Now()}); err == nil {
+		t.Error("expected error without schedule on-call source")
+	}
+	if _, err := SimulateEscalation(testEscalationSteps(), nil); err == nil {
+		t.Error("expected error without options")
+	}
+}
+
+func TestEscalationSimulateEscalation(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	chainID := "null"
+	mux.HandleFunc("/api/v1/routes/RIYGUJXCPFHXY/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"id": "RIYGUJXCPFHXY", "integration_id": "CFRPV98RPR1U8", "escalation_chain_id": %s, "routing_regex": "us-west", "position": 0, "is_the_last_route": false}`, chainID)
+	})
+	mux.HandleFunc("/api/v1/escalation_policies", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		query := r.URL.Query()
+		if chainID == "null" {
+			if got := query.Get("route_id"); got != "RIYGUJXCPFHXY" {
+				t.Errorf("route_id is %q, want %q", got, "RIYGUJXCPFHXY")
+			}
+			fmt.Fprint(w, `{"count": 2, "next": null, "previous": null, "results": [
+				{"id": "E2", "route_id": "RIYGUJXCPFHXY", "position": 1, "type": "notify_on_call_from_schedule", "notify_on_call_from_schedule": "SBM7DV7BKFUYU"},
+				{"id": "E1", "route_id": "RIYGUJXCPFHXY", "position": 0, "type": "wait", "duration": 3600}
+			]}`)
+			return
+		}
+		if got := query.Get("escalation_chain_id"); got != "FWDL7M6N6I9HE" || query.Get("route_id") != "" {
+			t.Errorf("escalation_chain_id is %q with route_id %q, want %q only", got, query.Get("route_id"), "FWDL7M6N6I9HE")
+		}
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [
+			{"id": "EC1", "escalation_chain_id": "FWDL7M6N6I9HE", "position": 0, "type": "notify_on_call_from_schedule", "notify_on_call_from_schedule": "SBM7DV7BKFUYU"}
+		]}`)
+	})
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []}`)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [{
+			"id": "OH3V5FYQEYJ6M",
+			"schedule_id": "SBM7DV7BKFUYU",
+			"type": "recurrent_event",
+			"name": "Days",
+			"level": 0,
+			"start": "2020-09-04T09:00:00",
+			"duration": 28800,
+			"frequency": "daily",
+			"interval": 1,
+			"users": ["U4DNY931HHJS5"]
+		}]}`)
+	})
+
+	timeline, err := client.Escalations.SimulateEscalation("RIYGUJXCPFHXY", &EscalationSimulationOptions{
+		Start: time.Date(2020, 9, 10, 8, 30, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"08:30 r0 E1: wait 1h0m0s",
+		"09:30 r0 E2: notify U4DNY931HHJS5",
+	}
+	if got := formatEscalationEvents(timeline.Events); !reflect.DeepEqual(want, got) {
+		t.Errorf("events are\n %s\nwant\n %s", strings.Join(got, "\n "), strings.Join(want, "\n "))
+	}
+
+	// escalation chain of the route is simulated
+	chainID = `"FWDL7M6N6I9HE"`
+	timeline, err = client.Escalations.SimulateEscalation("RIYGUJXCPFHXY", &EscalationSimulationOptions{
+		Start: time.Date(2020, 9, 10, 8, 30, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"08:30 r0 EC1: nobody is on call in schedule SBM7DV7BKFUYU"}
+	if got := formatEscalationEvents(timeline.Events); !reflect.DeepEqual(want, got) {
+		t.Errorf("events are\n %s\nwant\n %s", strings.Join(got, "\n "), strings.Join(want, "\n "))
+	}
+}
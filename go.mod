@@ -4,6 +4,8 @@ go 1.14
 
 require (
 	github.com/google/go-querystring v1.0.0
+	github.com/hashicorp/go-cleanhttp v0.5.1
 	github.com/hashicorp/go-retryablehttp v0.6.6
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
+	gopkg.in/yaml.v2 v2.4.0
 )
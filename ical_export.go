@@ -0,0 +1,404 @@
+package amixr
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalProductID = "-//amixr//amixr-go-client//EN"
+	// icalOverrideProperty marks events of override shifts
+	icalOverrideProperty = "X-AMIXR-OVERRIDE"
+	icalDateTimeLayout   = "20060102T150405"
+	icalMaxLineOctets    = 75
+)
+
+// ICalOptions configures iCalendar export of on-call shifts
+type ICalOptions struct {
+	// Window limits exported shift occurrences, required
+	Window Window
+	// User limits export to shift occurrences of the user with given id
+	User string
+	// Users are used to put usernames and emails into events instead of user ids.
+	// Export helpers of ScheduleService get all users when it is nil.
+	Users []*User
+	// Name is the calendar name shown by calendar apps
+	Name string
+	// Timestamp is the DTSTAMP of events, current time by default
+	Timestamp time.Time
+}
+
+// WriteICal writes shift occurrences of the schedule as RFC 5545 iCalendar document.
+// Every occurrence becomes an event with usernames as summary, prefixed with "[L<level>]"
+// for levels above zero. Occurrences are clipped to the time they are on call, so parts
+// taken by overrides or higher levels are left out, as are occurrences without users.
+func (calculator *OnCallCalculator) WriteICal(w io.Writer, opt *ICalOptions) error {
+	return writeICal(w, []*OnCallCalculator{calculator}, opt)
+}
+
+// ExportICal writes on-call shifts of the calendar schedule as iCalendar document, see OnCallCalculator.WriteICal
+func (service *ScheduleService) ExportICal(scheduleID string, w io.Writer, opt *ICalOptions, options ...RequestOption) error {
+	if opt == nil {
+		return fmt.Errorf("iCal options required")
+	}
+	calculator, err := service.GetOnCallCalculator(scheduleID, options...)
+	if err != nil {
+		return err
+	}
+	export := *opt
+	if export.Name == "" {
+		export.Name = calculator.schedule.Name
+	}
+	if err := service.exportUsers(&export, options); err != nil {
+		return err
+	}
+	return calculator.WriteICal(w, &export)
+}
+
+// ExportUserICal writes on-call shifts of the user from all calendar schedules as iCalendar document.
+// Schedules of ical type are skipped since their shifts come from an external calendar.
+func (service *ScheduleService) ExportUserICal(userID string, w io.Writer, opt *ICalOptions, options ...RequestOption) error {
+	if opt == nil {
+		return fmt.Errorf("iCal options required")
+	}
+	if userID == "" {
+		return fmt.Errorf("user required")
+	}
+	schedules, err := service.ListAllSchedules(&ListScheduleOptions{}, options...)
+	if err != nil {
+		return err
+	}
+
+	var calculators []*OnCallCalculator
+	for _, schedule := range schedules {
+		if schedule.Type == ScheduleTypeICal {
+			continue
+		}
+		shifts, err := service.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: schedule.ID}, options...)
+		if err != nil {
+			return err
+		}
+		calculator, err := NewOnCallCalculator(schedule, shifts)
+		if err != nil {
+			return err
+		}
+		calculators = append(calculators, calculator)
+	}
+
+	export := *opt
+	export.User = userID
+	if err := service.exportUsers(&export, options); err != nil {
+		return err
+	}
+	if export.Name == "" {
+		export.Name = "On-call shifts of " + userID
+		for _, user := range export.Users {
+			if user.ID == userID && user.Username != "" {
+				export.Name = "On-call shifts of " + user.Username
+			}
+		}
+	}
+	return writeICal(w, calculators, &export)
+}
+
+func (service *ScheduleService) exportUsers(opt *ICalOptions, options []RequestOption) error {
+	if opt.Users != nil {
+		return nil
+	}
+	users, err := service.client.Users.ListAllUsers(&ListUserOptions{}, options...)
+	if err != nil {
+		return err
+	}
+	opt.Users = users
+	return nil
+}
+
+func writeICal(w io.Writer, calculators []*OnCallCalculator, opt *ICalOptions) error {
+	if opt == nil {
+		return fmt.Errorf("iCal options required")
+	}
+	if err := opt.Window.validate(); err != nil {
+		return err
+	}
+
+	type event struct {
+		occurrence *ShiftOccurrence
+		location   *time.Location
+	}
+	var events []event
+	for _, calculator := range calculators {
+		occurrences, err := calculator.Occurrences(opt.Window)
+		if err != nil {
+			return err
+		}
+		for _, occurrence := range effectiveOccurrences(occurrences) {
+			if opt.User != "" && !containsString(occurrence.Users, opt.User) {
+				continue
+			}
+			events = append(events, event{occurrence: occurrence, location: calculator.location})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].occurrence.Start.Before(events[j].occurrence.Start)
+	})
+
+	// time zone definitions have to cover all events
+	spans := make(map[string]*Window)
+	locations := make(map[string]*time.Location)
+	for _, e := range events {
+		if e.location == time.UTC {
+			continue
+		}
+		name := e.location.String()
+		span, ok := spans[name]
+		if !ok {
+			span = &Window{Start: e.occurrence.Start, End: e.occurrence.End}
+			spans[name] = span
+			locations[name] = e.location
+		}
+		if e.occurrence.Start.Before(span.Start) {
+			span.Start = e.occurrence.Start
+		}
+		if e.occurrence.End.After(span.End) {
+			span.End = e.occurrence.End
+		}
+	}
+	var zones []string
+	for name := range spans {
+		zones = append(zones, name)
+	}
+	sort.Strings(zones)
+
+	users := make(map[string]*User)
+	for _, user := range opt.Users {
+		users[user.ID] = user
+	}
+	timestamp := opt.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	iw := &icalWriter{w: w}
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", icalProductID)
+	iw.line("CALSCALE", "GREGORIAN")
+	iw.line("METHOD", "PUBLISH")
+	if opt.Name != "" {
+		iw.line("X-WR-CALNAME", icalEscape(opt.Name))
+	}
+	for _, name := range zones {
+		iw.timezone(locations[name], *spans[name])
+	}
+	for _, e := range events {
+		iw.event(e.occurrence, e.location, users, timestamp)
+	}
+	iw.line("END", "VCALENDAR")
+	return iw.err
+}
+
+// effectiveOccurrences clips occurrences to the time they win over overrides and higher levels,
+// like resolveOnCallIntervals does, dropping occurrences which never win
+func effectiveOccurrences(occurrences []*ShiftOccurrence) []*ShiftOccurrence {
+	var bounds []time.Time
+	for _, occurrence := range occurrences {
+		bounds = append(bounds, occurrence.Start, occurrence.End)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	var pieces []*ShiftOccurrence
+	open := make(map[*ShiftOccurrence]*ShiftOccurrence)
+	for i := 0; i+1 < len(bounds); i++ {
+		start, end := bounds[i], bounds[i+1]
+		if !end.After(start) {
+			continue
+		}
+		for _, winner := range winningOccurrences(occurrences, start, end) {
+			if piece, ok := open[winner]; ok && piece.End.Equal(start) {
+				piece.End = end
+				continue
+			}
+			piece := *winner
+			piece.Start, piece.End = start, end
+			open[winner] = &piece
+			pieces = append(pieces, &piece)
+		}
+	}
+	return pieces
+}
+
+// icalWriter writes folded content lines, keeping the first error
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (iw *icalWriter) line(name, value string) {
+	if iw.err != nil {
+		return
+	}
+	_, iw.err = io.WriteString(iw.w, icalFold(name+":"+value))
+}
+
+func (iw *icalWriter) dateTime(name string, t time.Time, loc *time.Location) {
+	if loc == time.UTC {
+		iw.line(name, t.UTC().Format(icalDateTimeLayout)+"Z")
+		return
+	}
+	iw.line(name+";TZID="+loc.String(), t.In(loc).Format(icalDateTimeLayout))
+}
+
+func (iw *icalWriter) event(occurrence *ShiftOccurrence, loc *time.Location, users map[string]*User, timestamp time.Time) {
+	var names []string
+	for _, id := range occurrence.Users {
+		if user, ok := users[id]; ok && user.Username != "" {
+			names = append(names, user.Username)
+		} else {
+			names = append(names, id)
+		}
+	}
+	summary := strings.Join(names, " ")
+	if occurrence.Level > 0 {
+		summary = fmt.Sprintf("[L%d] %s", occurrence.Level, summary)
+	}
+
+	uid := "occurrence"
+	if occurrence.Shift != nil {
+		uid = occurrence.Shift.ID
+	}
+
+	iw.line("BEGIN", "VEVENT")
+	iw.line("UID", fmt.Sprintf("%s-%s@amixr", uid, occurrence.Start.UTC().Format(icalDateTimeLayout+"Z")))
+	iw.line("DTSTAMP", timestamp.UTC().Format(icalDateTimeLayout)+"Z")
+	iw.dateTime("DTSTART", occurrence.Start, loc)
+	iw.dateTime("DTEND", occurrence.End, loc)
+	iw.line("SUMMARY", icalEscape(summary))
+	if occurrence.Shift != nil && occurrence.Shift.Name != "" {
+		iw.line("DESCRIPTION", icalEscape(occurrence.Shift.Name))
+	}
+	for _, id := range occurrence.Users {
+		if user, ok := users[id]; ok && user.Email != "" {
+			iw.line("ATTENDEE;CN="+icalParam(user.Username), "mailto:"+user.Email)
+		}
+	}
+	if occurrence.Override {
+		iw.line(icalOverrideProperty, "TRUE")
+	}
+	iw.line("END", "VEVENT")
+}
+
+// timezone writes VTIMEZONE with an observance for every offset change of loc within the span.
+// Go locations don't expose their rules, so transitions are found by scanning.
+func (iw *icalWriter) timezone(loc *time.Location, span Window) {
+	start := span.Start.In(loc).Truncate(time.Second)
+	iw.line("BEGIN", "VTIMEZONE")
+	iw.line("TZID", loc.String())
+	iw.observance(start, start)
+	for t := start; t.Before(span.End); {
+		next := t.Add(24 * time.Hour)
+		if _, offset := t.Zone(); offset != zoneOffset(next) {
+			// binary search for the first second with the new offset
+			lo, hi := t, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+				if zoneOffset(mid) == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			iw.observance(lo, hi)
+			next = hi
+		}
+		t = next
+	}
+	iw.line("END", "VTIMEZONE")
+}
+
+// observance writes STANDARD or DAYLIGHT component for offset which starts at onset,
+// before is a moment just before the onset
+func (iw *icalWriter) observance(before, onset time.Time) {
+	name, offset := onset.Zone()
+	_, offsetFrom := before.Zone()
+
+	component := "STANDARD"
+	january := time.Date(onset.Year(), time.January, 1, 0, 0, 0, 0, onset.Location())
+	july := time.Date(onset.Year(), time.July, 1, 0, 0, 0, 0, onset.Location())
+	if offset > zoneOffset(january) || offset > zoneOffset(july) {
+		component = "DAYLIGHT"
+	}
+
+	iw.line("BEGIN", component)
+	// onset is given in local time before the change
+	iw.line("DTSTART", onset.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(icalDateTimeLayout))
+	iw.line("TZOFFSETFROM", icalOffset(offsetFrom))
+	iw.line("TZOFFSETTO", icalOffset(offset))
+	iw.line("TZNAME", icalEscape(name))
+	iw.line("END", component)
+}
+
+func zoneOffset(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
+}
+
+func icalOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	if seconds := offset % 60; seconds != 0 {
+		s += fmt.Sprintf("%02d", seconds)
+	}
+	return s
+}
+
+// icalEscape escapes TEXT value
+func icalEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// icalParam quotes parameter value, which can't contain double quotes at all
+func icalParam(s string) string {
+	return `"` + strings.Replace(s, `"`, "", -1) + `"`
+}
+
+// icalFold splits content line into lines of at most 75 octets without breaking UTF-8 sequences
+func icalFold(line string) string {
+	var b strings.Builder
+	limit := icalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space
+		limit = icalMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
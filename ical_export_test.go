@@ -0,0 +1,297 @@
+package amixr
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testICalTimestamp = time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func TestWriteICalTimeZone(t *testing.T) {
+	shift := testWeekly(testCalculatorShift("OH3V5FYQEYJ6M", OnCallShiftTypeRecurrentEvent, "2020-10-19T09:00:00", 3600, 1, "U4DNY931HHJS5"))
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU", TimeZone: "Europe/Berlin"}, []*OnCallShift{shift})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = calculator.WriteICal(&buf, &ICalOptions{
+		Window:    Window{Start: time.Date(2020, 10, 19, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 10, 27, 0, 0, 0, 0, time.UTC)},
+		Users:     []*User{testUser},
+		Name:      "Primary, EU",
+		Timestamp: testICalTimestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//amixr//amixr-go-client//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Primary\, EU`,
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20201019T090000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20201025T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:OH3V5FYQEYJ6M-20201019T070000Z@amixr",
+		"DTSTAMP:20201001T120000Z",
+		"DTSTART;TZID=Europe/Berlin:20201019T090000",
+		"DTEND;TZID=Europe/Berlin:20201019T100000",
+		"SUMMARY:[L1] alex",
+		"DESCRIPTION:OH3V5FYQEYJ6M",
+		`ATTENDEE;CN="alex":mailto:public-api-demo-user-1@amixr.io`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:OH3V5FYQEYJ6M-20201026T080000Z@amixr",
+		"DTSTAMP:20201001T120000Z",
+		"DTSTART;TZID=Europe/Berlin:20201026T090000",
+		"DTEND;TZID=Europe/Berlin:20201026T100000",
+		"SUMMARY:[L1] alex",
+		"DESCRIPTION:OH3V5FYQEYJ6M",
+		`ATTENDEE;CN="alex":mailto:public-api-demo-user-1@amixr.io`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := buf.String(); got != want {
+		t.Errorf("returned\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteICalUser(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = calculator.WriteICal(&buf, &ICalOptions{
+		Window:    Window{Start: time.Date(2020, 9, 13, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 15, 0, 0, 0, 0, time.UTC)},
+		User:      "F",
+		Timestamp: testICalTimestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VEVENT",
+		"UID:override-20200914T090000Z@amixr",
+		"DTSTAMP:20201001T120000Z",
+		"DTSTART:20200914T090000Z",
+		"DTEND:20200914T130000Z",
+		"SUMMARY:F",
+		"DESCRIPTION:override",
+		"X-AMIXR-OVERRIDE:TRUE",
+		"END:VEVENT",
+	}, "\r\n")
+	got := buf.String()
+	if !strings.Contains(got, want) {
+		t.Errorf("returned\n%s\nwant event\n%s", got, want)
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("exported %d events, want 1", n)
+	}
+	if strings.Contains(got, "VTIMEZONE") {
+		t.Error("UTC schedule should not have VTIMEZONE")
+	}
+
+	if err := calculator.WriteICal(&buf, &ICalOptions{}); err == nil {
+		t.Error("expected error without window")
+	}
+}
+
+func TestWriteICalResolved(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		user   string
+		start  time.Time
+		events []string
+	}{
+		{"higher level", "A", time.Date(2020, 9, 9, 0, 0, 0, 0, time.UTC), []string{
+			"DTSTART:20200909T090000Z\r\nDTEND:20200909T120000Z",
+			"DTSTART:20200909T140000Z\r\nDTEND:20200909T210000Z",
+		}},
+		{"override", "B", time.Date(2020, 9, 14, 0, 0, 0, 0, time.UTC), []string{
+			"DTSTART:20200914T130000Z\r\nDTEND:20200914T210000Z",
+		}},
+		{"lower level", "D", time.Date(2020, 9, 9, 0, 0, 0, 0, time.UTC), []string{
+			"DTSTART:20200909T120000Z\r\nDTEND:20200909T140000Z",
+		}},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		err := calculator.WriteICal(&buf, &ICalOptions{
+			Window:    Window{Start: test.start, End: test.start.AddDate(0, 0, 1)},
+			User:      test.user,
+			Timestamp: testICalTimestamp,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := buf.String()
+		if n := strings.Count(got, "BEGIN:VEVENT"); n != len(test.events) {
+			t.Errorf("%s: exported %d events, want %d\n%s", test.name, n, len(test.events), got)
+		}
+		for _, event := range test.events {
+			if !strings.Contains(got, event) {
+				t.Errorf("%s: returned\n%s\nwant event with\n%s", test.name, got, event)
+			}
+		}
+	}
+}
+
+func TestICalFold(t *testing.T) {
+	tests := []string{
+		"SUMMARY:short",
+		"DESCRIPTION:" + strings.Repeat("a", 200),
+		"DESCRIPTION:" + strings.Repeat("дежурство ", 30),
+		"DESCRIPTION:" + strings.Repeat("a", 63),
+		"DESCRIPTION:" + strings.Repeat("a", 64),
+	}
+	for _, line := range tests {
+		folded := icalFold(line)
+		if !strings.HasSuffix(folded, "\r\n") {
+			t.Errorf("%q does not end with CRLF", folded)
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+			if len(l) > icalMaxLineOctets {
+				t.Errorf("line %q is %d octets long", l, len(l))
+			}
+		}
+		if unfolded := strings.Replace(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "", -1); unfolded != line {
+			t.Errorf("unfolded %q, want %q", unfolded, line)
+		}
+	}
+}
+
+func TestICalEscape(t *testing.T) {
+	if got, want := icalEscape("a,b;c\\d\ne"), `a\,b\;c\\d\ne`; got != want {
+		t.Errorf("escaped %q, want %q", got, want)
+	}
+	if got, want := icalOffset(-(5*3600 + 30*60)), "-0530"; got != want {
+		t.Errorf("offset %q, want %q", got, want)
+	}
+	if got, want := icalOffset(3600+45), "+010045"; got != want {
+		t.Errorf("offset %q, want %q", got, want)
+	}
+}
+
+func setupICalExport(t *testing.T) (*Client, func()) {
+	mux, server, client := setup(t)
+
+	mux.HandleFunc("/api/v1/schedules/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"count": 2, "next": null, "previous": null, "results": [
+			{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []},
+			{"id": "SICAL", "type": "ical", "name": "External", "ical_url": "https://example.com/on-call.ics", "on_call_now": []}
+		]}`)
+	})
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []}`)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.URL.Query().Get("schedule_id"); got != "SBM7DV7BKFUYU" {
+			t.Errorf("schedule_id is %q, want %q", got, "SBM7DV7BKFUYU")
+		}
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [{
+			"id": "OH3V5FYQEYJ6M",
+			"schedule_id": "SBM7DV7BKFUYU",
+			"type": "recurrent_event",
+			"name": "Daily",
+			"level": 0,
+			"start": "2020-09-04T16:00:00",
+			"duration": 3600,
+			"frequency": "daily",
+			"interval": 1,
+			"users": ["U4DNY931HHJS5"]
+		}]}`)
+	})
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserBody)
+	})
+
+	return client, func() { teardown(server) }
+}
+
+func TestExportICal(t *testing.T) {
+	client, teardown := setupICalExport(t)
+	defer teardown()
+
+	var buf bytes.Buffer
+	err := client.Schedules.ExportICal("SBM7DV7BKFUYU", &buf, &ICalOptions{
+		Window:    Window{Start: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 12, 0, 0, 0, 0, time.UTC)},
+		Timestamp: testICalTimestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"X-WR-CALNAME:Primary\r\n", "SUMMARY:alex\r\n", "DTSTART:20200910T160000Z\r\n", "DTSTART:20200911T160000Z\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("returned\n%s\nwant line %q", got, want)
+		}
+	}
+
+	if err := client.Schedules.ExportICal("SBM7DV7BKFUYU", &buf, nil); err == nil {
+		t.Error("expected error without options")
+	}
+}
+
+func TestExportUserICal(t *testing.T) {
+	client, teardown := setupICalExport(t)
+	defer teardown()
+
+	var buf bytes.Buffer
+	err := client.Schedules.ExportUserICal("U4DNY931HHJS5", &buf, &ICalOptions{
+		Window:    Window{Start: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC)},
+		Timestamp: testICalTimestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"X-WR-CALNAME:On-call shifts of alex\r\n", "UID:OH3V5FYQEYJ6M-20200910T160000Z@amixr\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("returned\n%s\nwant line %q", got, want)
+		}
+	}
+
+	buf.Reset()
+	err = client.Schedules.ExportUserICal("UNKNOWN", &buf, &ICalOptions{
+		Window: Window{Start: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "BEGIN:VEVENT") {
+		t.Errorf("exported events of other users\n%s", buf.String())
+	}
+}
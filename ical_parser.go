@@ -0,0 +1,729 @@
+package amixr
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// icalLevelPrefix is the priority level prefix of event summary, e.g. "[L2] alex"
+var icalLevelPrefix = regexp.MustCompile(`^\s*\[[Ll](\d+)\]\s*`)
+
+// icalUserLike matches summary words which look like a username or an email, other words
+// such as "On call:" are not reported as unknown users
+var icalUserLike = regexp.MustCompile(`^@?[a-z0-9][a-z0-9._-]*$|^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// ICalCalendar is a parsed iCal feed of an ical schedule. Use ParseICal to create one.
+type ICalCalendar struct {
+	// Name is the calendar name from X-WR-CALNAME
+	Name   string
+	Events []*ICalEvent
+	// Warnings describe events which were skipped, because they use recurrence rules
+	// or time zones this client doesn't support
+	Warnings []string
+
+	location *time.Location
+}
+
+// ICalEvent is an on-call event of iCal feed. Users are found by username or email in
+// the summary, optionally prefixed with "[L<level>]" priority level, and by attendee emails.
+type ICalEvent struct {
+	UID     string
+	Summary string
+	// Start and End of the first occurrence
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+	Level  int
+	// Override is set for events exported from override shifts
+	Override bool
+	// Users are ids of users on call during the event
+	Users []string
+	// UnknownUsers are summary words which look like a username or an email but match no user
+	UnknownUsers []string
+
+	recurrence *recurrence
+	// duration is set when the event has DURATION instead of DTEND
+	duration *icalDuration
+}
+
+// icalUnsupportedError is a valid iCal feature this client doesn't support, events using it are skipped
+type icalUnsupportedError string
+
+func (e icalUnsupportedError) Error() string {
+	return string(e)
+}
+
+// ParseICal parses iCal feed. Users are matched by username or email against given users.
+// Times without time zone are in the calendar time zone given by X-WR-TIMEZONE, or UTC.
+// Time zones are IANA or Windows names, or VTIMEZONE definitions of the feed.
+// Events with unsupported recurrence rules or time zones are skipped with a warning.
+func ParseICal(r io.Reader, users []*User) (*ICalCalendar, error) {
+	lines, err := icalUnfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ICalCalendar{location: time.UTC}
+	var events [][]icalProperty
+	var timezones []*icalTimezone
+	var components []string
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("iCal line %d: %w", n+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(prop.value))
+			switch {
+			case len(components) == 2 && components[1] == "VEVENT":
+				events = append(events, nil)
+			case len(components) == 2 && components[1] == "VTIMEZONE":
+				timezones = append(timezones, &icalTimezone{})
+			case len(components) == 3 && components[1] == "VTIMEZONE" && components[2] == "DAYLIGHT":
+				timezones[len(timezones)-1].daylight = true
+			}
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("iCal line %d: unexpected END:%s", n+1, prop.value)
+			}
+			components = components[:len(components)-1]
+			continue
+		}
+
+		switch {
+		case len(components) == 1 && components[0] == "VCALENDAR":
+			switch prop.name {
+			case "X-WR-CALNAME":
+				calendar.Name = icalUnescape(prop.value)
+			case "X-WR-TIMEZONE":
+				loc, err := loadICalLocation(prop.value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid calendar time zone %q: %w", prop.value, err)
+				}
+				calendar.location = loc
+			}
+		case len(components) == 2 && components[1] == "VEVENT":
+			events[len(events)-1] = append(events[len(events)-1], prop)
+		case len(components) == 2 && components[1] == "VTIMEZONE":
+			switch prop.name {
+			case "TZID":
+				timezones[len(timezones)-1].tzid = prop.value
+			case "X-LIC-LOCATION":
+				timezones[len(timezones)-1].licLocation = prop.value
+			}
+		case len(components) == 3 && components[1] == "VTIMEZONE" && components[2] == "STANDARD":
+			if prop.name == "TZOFFSETTO" {
+				tz := timezones[len(timezones)-1]
+				tz.offsets = append(tz.offsets, prop.value)
+			}
+		}
+	}
+	if len(components) > 0 {
+		return nil, fmt.Errorf("iCal feed ends inside %s", components[len(components)-1])
+	}
+
+	parser := &icalEventParser{
+		location:  calendar.location,
+		locations: make(map[string]*time.Location),
+		timezones: make(map[string]*icalTimezone),
+		users:     users,
+	}
+	for _, tz := range timezones {
+		parser.timezones[tz.tzid] = tz
+	}
+	byUID := make(map[string]*ICalEvent)
+	var instances []*icalInstance
+	for _, props := range events {
+		event, instance, err := parser.parse(props)
+		var unsupported icalUnsupportedError
+		if errors.As(err, &unsupported) {
+			calendar.Warnings = append(calendar.Warnings, fmt.Sprintf("skipped %s", err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if instance != nil {
+			instances = append(instances, instance)
+		}
+		if event == nil {
+			continue
+		}
+		calendar.Events = append(calendar.Events, event)
+		if instance == nil && event.UID != "" {
+			byUID[event.UID] = event
+		}
+	}
+
+	// modified or cancelled instances of recurring events replace their original occurrence
+	for _, instance := range instances {
+		if parent, ok := byUID[instance.uid]; ok && parent.recurrence.frequency != "" {
+			parent.recurrence.exclude = append(parent.recurrence.exclude, instance.recurrenceID)
+		}
+	}
+	return calendar, nil
+}
+
+// ParseICal parses iCal feed, matching users against all users of the team, see ParseICal
+func (service *ScheduleService) ParseICal(r io.Reader, options ...RequestOption) (*ICalCalendar, error) {
+	users, err := service.client.Users.ListAllUsers(&ListUserOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseICal(r, users)
+}
+
+// GetICalCalendar downloads and parses iCal feed of the ical schedule.
+// The feed URL is requested without API token.
+func (service *ScheduleService) GetICalCalendar(scheduleID string, options ...RequestOption) (*ICalCalendar, error) {
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := service.downloadICal(schedule, options)
+	if err != nil {
+		return nil, err
+	}
+	return service.ParseICal(feed, options...)
+}
+
+func (service *ScheduleService) downloadICal(schedule *Schedule, options []RequestOption) (io.Reader, error) {
+	if schedule.ICalUrl == nil || *schedule.ICalUrl == "" {
+		return nil, fmt.Errorf("schedule %s has no iCal URL", schedule.ID)
+	}
+	u, err := url.Parse(*schedule.ICalUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iCal URL of schedule %s: %w", schedule.ID, err)
+	}
+	if u.Scheme == "webcal" {
+		u.Scheme = "https"
+	}
+
+	req, err := retryablehttp.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if service.client.userAgent != "" {
+		req.Header.Set("User-Agent", service.client.userAgent)
+	}
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
+	feed := new(bytes.Buffer)
+	if _, err := service.client.Do(req, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Location returns the calendar time zone
+func (calendar *ICalCalendar) Location() *time.Location {
+	return calendar.location
+}
+
+// Occurrences expands events into occurrences intersecting the window, ordered by start.
+// Occurrences are not clipped to the window.
+func (calendar *ICalCalendar) Occurrences(window Window) ([]*ShiftOccurrence, error) {
+	if err := window.validate(); err != nil {
+		return nil, err
+	}
+	var occurrences []*ShiftOccurrence
+	for _, event := range calendar.Events {
+		event := event
+		err := event.recurrence.expand(event.Start, window.End, func(start time.Time, _ int) bool {
+			end := event.end(start)
+			if window.Overlaps(start, end) {
+				occurrences = append(occurrences, &ShiftOccurrence{
+					Event:    event,
+					Start:    start,
+					End:      end,
+					Users:    event.Users,
+					Level:    event.Level,
+					Override: event.Override,
+				})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("iCal event %s: %w", event.UID, err)
+		}
+	}
+	sortOccurrences(occurrences)
+	return occurrences, nil
+}
+
+// OnCallIntervals returns intervals within the window when someone is on call, ordered by start.
+// Adjacent intervals always differ in users, time nobody is on call is left out.
+func (calendar *ICalCalendar) OnCallIntervals(window Window) ([]*OnCallInterval, error) {
+	occurrences, err := calendar.Occurrences(window)
+	if err != nil {
+		return nil, err
+	}
+	return resolveOnCallIntervals(occurrences, window), nil
+}
+
+// WhoIsOnCall returns sorted ids of users on call at given time
+func (calendar *ICalCalendar) WhoIsOnCall(at time.Time) ([]string, error) {
+	return whoIsOnCall(calendar, at)
+}
+
+// end returns end of occurrence, all-day events and days of DURATION keep their length in days over DST changes
+func (event *ICalEvent) end(start time.Time) time.Time {
+	if event.duration != nil {
+		return event.duration.add(start)
+	}
+	if event.AllDay {
+		days := int(civilDate(event.End).Sub(civilDate(event.Start)) / (24 * time.Hour))
+		return start.AddDate(0, 0, days)
+	}
+	return start.Add(event.End.Sub(event.Start))
+}
+
+// icalInstance is a RECURRENCE-ID reference to an occurrence of a recurring event
+type icalInstance struct {
+	uid          string
+	recurrenceID time.Time
+}
+
+type icalEventParser struct {
+	location  *time.Location
+	locations map[string]*time.Location
+	timezones map[string]*icalTimezone
+	users     []*User
+}
+
+func (p *icalEventParser) parse(props []icalProperty) (*ICalEvent, *icalInstance, error) {
+	event := &ICalEvent{}
+	var instance *icalInstance
+	var duration *icalProperty
+	var rrule *icalProperty
+	var exdates []icalProperty
+	var attendees []string
+	cancelled := false
+	hasEnd := false
+
+	for i := range props {
+		prop := props[i]
+		var err error
+		switch prop.name {
+		case "UID":
+			event.UID = prop.value
+		case "SUMMARY":
+			event.Summary = icalUnescape(prop.value)
+		case "DTSTART":
+			event.Start, event.AllDay, err = p.time(prop)
+		case "DTEND":
+			event.End, _, err = p.time(prop)
+			hasEnd = true
+		case "DURATION":
+			duration = &props[i]
+		case "RRULE":
+			rrule = &props[i]
+		case "EXDATE":
+			exdates = append(exdates, prop)
+		case "RECURRENCE-ID":
+			instance = &icalInstance{}
+			instance.recurrenceID, _, err = p.time(prop)
+		case "STATUS":
+			cancelled = strings.EqualFold(prop.value, "CANCELLED")
+		case "ATTENDEE":
+			if email := strings.TrimSpace(prop.value); len(email) > 7 && strings.EqualFold(email[:7], "mailto:") {
+				attendees = append(attendees, email[7:])
+			}
+		case icalOverrideProperty:
+			event.Override = strings.EqualFold(prop.value, "TRUE")
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("iCal event %s: %w", event.UID, err)
+		}
+	}
+
+	if event.Start.IsZero() {
+		return nil, nil, fmt.Errorf("iCal event %s has no start", event.UID)
+	}
+	if instance != nil {
+		instance.uid = event.UID
+	}
+	if cancelled {
+		return nil, instance, nil
+	}
+
+	switch {
+	case hasEnd:
+	case duration != nil:
+		d, err := parseICalDuration(duration.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("iCal event %s: %w", event.UID, err)
+		}
+		event.duration = &d
+		event.End = d.add(event.Start)
+	case event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+	if event.End.Before(event.Start) {
+		return nil, nil, fmt.Errorf("iCal event %s ends before start", event.UID)
+	}
+
+	event.recurrence = &recurrence{interval: 1, weekStart: time.Monday}
+	if rrule != nil && instance == nil {
+		if err := p.rrule(event.recurrence, rrule.value, event.Start.Location()); err != nil {
+			return nil, nil, fmt.Errorf("iCal event %s: %w", event.UID, err)
+		}
+		for _, exdate := range exdates {
+			for _, value := range strings.Split(exdate.value, ",") {
+				exdate.value = value
+				t, _, err := p.time(exdate)
+				if err != nil {
+					return nil, nil, fmt.Errorf("iCal event %s: %w", event.UID, err)
+				}
+				event.recurrence.exclude = append(event.recurrence.exclude, t)
+			}
+		}
+	}
+
+	p.matchUsers(event, attendees)
+	return event, instance, nil
+}
+
+func (p *icalEventParser) time(prop icalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if prop.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, p.location)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s date %q", prop.name, value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalDateTimeLayout+"Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s time %q", prop.name, value)
+		}
+		return t, false, nil
+	}
+
+	loc := p.location
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = p.zone(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("time zone of %s: %w", prop.name, err)
+		}
+	}
+	t, err := time.ParseInLocation(icalDateTimeLayout, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s time %q", prop.name, value)
+	}
+	return t, false, nil
+}
+
+// zone returns location of TZID, trying IANA and Windows names before VTIMEZONE of the feed
+func (p *icalEventParser) zone(tzid string) (*time.Location, error) {
+	if loc, ok := p.locations[tzid]; ok {
+		return loc, nil
+	}
+	loc, err := loadICalLocation(tzid)
+	if err != nil {
+		tz, ok := p.timezones[tzid]
+		if !ok {
+			return nil, icalUnsupportedError(fmt.Sprintf("unknown time zone %q", tzid))
+		}
+		if loc, err = tz.location(); err != nil {
+			return nil, err
+		}
+	}
+	p.locations[tzid] = loc
+	return loc, nil
+}
+
+func (p *icalEventParser) rrule(r *recurrence, value string, loc *time.Location) error {
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		eq := strings.Index(part, "=")
+		if eq < 0 {
+			return fmt.Errorf("invalid RRULE part %q", part)
+		}
+		key, value := strings.ToUpper(part[:eq]), part[eq+1:]
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch strings.ToUpper(value) {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.frequency = strings.ToLower(value)
+			default:
+				return icalUnsupportedError(fmt.Sprintf("unsupported RRULE frequency %q", value))
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+		case "UNTIL":
+			until, allDay, terr := p.time(icalProperty{name: "UNTIL", value: value, params: map[string]string{}})
+			if terr != nil {
+				return terr
+			}
+			if allDay {
+				// the whole last day is included
+				until = time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, loc)
+			}
+			r.until = until
+		case "WKST":
+			r.weekStart, err = parseWeekday(value)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, werr := parseWeekdayNum(day)
+				if werr != nil {
+					return werr
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "BYMONTH":
+			r.byMonth, err = parseICalInts(value)
+		case "BYMONTHDAY":
+			r.byMonthday, err = parseICalInts(value)
+		default:
+			return icalUnsupportedError(fmt.Sprintf("unsupported RRULE part %s", key))
+		}
+		if err != nil {
+			return fmt.Errorf("invalid RRULE %s %q: %w", key, value, err)
+		}
+	}
+	if r.frequency == "" {
+		return fmt.Errorf("RRULE %q has no frequency", value)
+	}
+	return r.validate()
+}
+
+// matchUsers matches summary words and attendee emails against usernames and emails
+func (p *icalEventParser) matchUsers(event *ICalEvent, attendees []string) {
+	summary := event.Summary
+	if m := icalLevelPrefix.FindStringSubmatch(summary); m != nil {
+		event.Level, _ = strconv.Atoi(m[1])
+		summary = summary[len(m[0]):]
+	}
+
+	add := func(name string) bool {
+		name = strings.TrimPrefix(name, "@")
+		for _, user := range p.users {
+			if strings.EqualFold(user.Username, name) || (user.Email != "" && strings.EqualFold(user.Email, name)) {
+				if !containsString(event.Users, user.ID) {
+					event.Users = append(event.Users, user.ID)
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	words := strings.FieldsFunc(summary, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ',' || r == ';' || r == '\n'
+	})
+	for _, word := range words {
+		if !add(word) && icalUserLike.MatchString(word) && !containsString(event.UnknownUsers, word) {
+			event.UnknownUsers = append(event.UnknownUsers, word)
+		}
+	}
+	for _, email := range attendees {
+		add(email)
+	}
+}
+
+// icalProperty is a content line, parameter names are upper case and only their first value is kept
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseICalLine(line string) (icalProperty, error) {
+	prop := icalProperty{params: make(map[string]string)}
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, fmt.Errorf("invalid content line %q", line)
+	}
+	prop.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		line = line[i+1:]
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return prop, fmt.Errorf("invalid parameter in %s", prop.name)
+		}
+		name := strings.ToUpper(line[:eq])
+		line = line[eq+1:]
+
+		first := true
+		for {
+			var value string
+			if strings.HasPrefix(line, `"`) {
+				end := strings.Index(line[1:], `"`)
+				if end < 0 {
+					return prop, fmt.Errorf("unterminated parameter %s of %s", name, prop.name)
+				}
+				value, line = line[1:end+1], line[end+2:]
+			} else {
+				end := strings.IndexAny(line, ",;:")
+				if end < 0 {
+					return prop, fmt.Errorf("invalid parameter %s of %s", name, prop.name)
+				}
+				value, line = line[:end], line[end:]
+			}
+			if first {
+				prop.params[name] = value
+				first = false
+			}
+			if !strings.HasPrefix(line, ",") {
+				break
+			}
+			line = line[1:]
+		}
+		if line == "" {
+			return prop, fmt.Errorf("missing value of %s", prop.name)
+		}
+		i = 0
+	}
+	if line[i] != ':' {
+		return prop, fmt.Errorf("invalid content line of %s", prop.name)
+	}
+	prop.value = line[i+1:]
+	return prop, nil
+}
+
+// icalUnfold reads content lines, joining continuation lines which start with space or tab
+func icalUnfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read iCal feed: %w", err)
+	}
+	return lines, nil
+}
+
+// icalUnescape reverses icalEscape
+func icalUnescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// icalDuration is DURATION value, days and weeks are nominal and keep local time over DST changes
+type icalDuration struct {
+	days  int
+	exact time.Duration
+}
+
+func (d icalDuration) add(t time.Time) time.Time {
+	return t.AddDate(0, 0, d.days).Add(d.exact)
+}
+
+// parseICalDuration parses DURATION value such as PT8H or P1DT12H
+func parseICalDuration(value string) (icalDuration, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	sign := 1
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return icalDuration{}, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	// days are counted in units of days, time in units of time
+	days := map[byte]int{'W': 7, 'D': 1}
+	var units map[byte]time.Duration
+	var d icalDuration
+	number := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number += string(c)
+		case c == 'T':
+			if number != "" || units != nil {
+				return icalDuration{}, fmt.Errorf("invalid duration %q", value)
+			}
+			days, units = nil, map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		default:
+			day, isDay := days[c]
+			unit, isUnit := units[c]
+			if !isDay && !isUnit || number == "" {
+				return icalDuration{}, fmt.Errorf("invalid duration %q", value)
+			}
+			n, _ := strconv.Atoi(number)
+			d.days += n * day
+			d.exact += time.Duration(n) * unit
+			number = ""
+		}
+	}
+	if number != "" {
+		return icalDuration{}, fmt.Errorf("invalid duration %q", value)
+	}
+	d.days *= sign
+	d.exact *= time.Duration(sign)
+	return d, nil
+}
+
+func parseICalInts(value string) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
@@ -0,0 +1,487 @@
+package amixr
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testICalUsers = []*User{
+	testUser,
+	{ID: "UBOB", Username: "bob", Email: "bob@example.com"},
+	{ID: "UCAROL", Username: "carol", Email: "carol@example.com"},
+}
+
+var testICalFeed = strings.Join([]string{
+	"BEGIN:VCALENDAR",
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+	"VERSION:2.0",
+	"X-WR-CALNAME:Ops on-call",
+	"X-WR-TIMEZONE:Europe/Berlin",
+	"BEGIN:VTIMEZONE",
+	"TZID:Europe/Berlin",
+	"BEGIN:STANDARD",
+	"DTSTART:19701025T030000",
+	"TZOFFSETFROM:+0200",
+	"TZOFFSETTO:+0100",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+	"BEGIN:VEVENT",
+	"UID:weekday@example.com",
+	"DTSTART;TZID=Europe/Berlin:20200907T090000",
+	"DTEND;TZID=Europe/Berlin:20200907T170000",
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20201030T000000Z",
+	"EXDATE;TZID=Europe/Berlin:20200909T090000",
+	"SUMMARY:alex",
+	`ATTENDEE;CN="Bob, Jr.";ROLE=REQ-PARTICIPANT:mailto:bob@example.com`,
+	"BEGIN:VALARM",
+	"ACTION:DISPLAY",
+	"DESCRIPTION:ignored",
+	"END:VALARM",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:weekday@example.com",
+	"RECURRENCE-ID;TZID=Europe/Berlin:20200910T090000",
+	"DTSTART;TZID=Europe/Berlin:20200910T120000",
+	"DTEND;TZID=Europe/Berlin:20200910T170000",
+	"SUMMARY:bob",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:weekday@example.com",
+	"RECURRENCE-ID;TZID=Europe/Berlin:20200911T090000",
+	"STATUS:CANCELLED",
+	"DTSTART;TZID=Europe/Berlin:20200911T090000",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:weekend@example.com",
+	"DTSTART;VALUE=DATE:20200912",
+	"DTEND;VALUE=DATE:20200914",
+	"RRULE:FREQ=WEEKLY;COUNT=2",
+	`SUMMARY:[L1] carol\, ghost`,
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:night@example.com",
+	"DTSTART:20200907T170000Z",
+	"DURATION:PT2H",
+	"SUMMARY:bob@exam",
+	" ple.com dave",
+	"END:VEVENT",
+	"END:VCALENDAR",
+	"",
+}, "\r\n")
+
+func TestParseICal(t *testing.T) {
+	calendar, err := ParseICal(strings.NewReader(testICalFeed), testICalUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calendar.Name != "Ops on-call" {
+		t.Errorf("name is %q, want %q", calendar.Name, "Ops on-call")
+	}
+	if got := calendar.Location().String(); got != "Europe/Berlin" {
+		t.Errorf("location is %s, want Europe/Berlin", got)
+	}
+
+	var events []string
+	for _, e := range calendar.Events {
+		events = append(events, fmt.Sprintf("%s %s %s %t L%d %v %v", e.UID, e.Start.Format(time.RFC3339), e.End.Format(time.RFC3339), e.AllDay, e.Level, e.Users, e.UnknownUsers))
+	}
+	wantEvents := []string{
+		"weekday@example.com 2020-09-07T09:00:00+02:00 2020-09-07T17:00:00+02:00 false L0 [U4DNY931HHJS5 UBOB] []",
+		"weekday@example.com 2020-09-10T12:00:00+02:00 2020-09-10T17:00:00+02:00 false L0 [UBOB] []",
+		"weekend@example.com 2020-09-12T00:00:00+02:00 2020-09-14T00:00:00+02:00 true L1 [UCAROL] [ghost]",
+		"night@example.com 2020-09-07T17:00:00Z 2020-09-07T19:00:00Z false L0 [UBOB] [dave]",
+	}
+	if !reflect.DeepEqual(wantEvents, events) {
+		t.Errorf("events are\n %v\nwant\n %v", events, wantEvents)
+	}
+
+	tests := []struct {
+		name  string
+		at    string
+		users []string
+	}{
+		{"recurring event with attendee", "2020-09-07T08:00:00Z", []string{"U4DNY931HHJS5", "UBOB"}},
+		{"event with duration", "2020-09-07T18:00:00Z", []string{"UBOB"}},
+		{"excluded date", "2020-09-09T08:00:00Z", nil},
+		{"moved instance original time", "2020-09-10T08:00:00Z", nil},
+		{"moved instance", "2020-09-10T11:00:00Z", []string{"UBOB"}},
+		{"cancelled instance", "2020-09-11T08:00:00Z", nil},
+		{"all-day event", "2020-09-12T12:00:00Z", []string{"UCAROL"}},
+		{"all-day event second day", "2020-09-13T21:59:59Z", []string{"UCAROL"}},
+		{"all-day event ends at local midnight", "2020-09-13T22:00:00Z", nil},
+		{"count", "2020-09-19T12:00:00Z", []string{"UCAROL"}},
+		{"count exhausted", "2020-09-26T12:00:00Z", nil},
+		{"wall time after DST change", "2020-10-26T15:30:00Z", []string{"U4DNY931HHJS5", "UBOB"}},
+		{"until", "2020-11-02T09:00:00Z", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, err := calendar.WhoIsOnCall(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.users, users) {
+				t.Errorf("on call are %v, want %v", users, tt.users)
+			}
+		})
+	}
+}
+
+func TestParseICalRoundTrip(t *testing.T) {
+	var users []*User
+	for _, id := range []string{"A", "B", "C", "D", "E", "F"} {
+		users = append(users, &User{ID: id, Username: "user-" + strings.ToLower(id)})
+	}
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU", TimeZone: "America/New_York"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := Window{Start: time.Date(2020, 9, 7, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 21, 0, 0, 0, 0, time.UTC)}
+
+	var buf bytes.Buffer
+	if err := calculator.WriteICal(&buf, &ICalOptions{Window: window, Users: users}); err != nil {
+		t.Fatal(err)
+	}
+	calendar, err := ParseICal(&buf, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	format := func(intervals []*OnCallInterval) []string {
+		var result []string
+		for _, i := range intervals {
+			result = append(result, fmt.Sprintf("%s %s %v", i.Start.UTC().Format(time.RFC3339), i.End.UTC().Format(time.RFC3339), i.Users))
+		}
+		return result
+	}
+	want, err := calculator.OnCallIntervals(window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := calendar.OnCallIntervals(window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(format(want), format(got)) {
+		t.Errorf("parsed intervals are\n %v\nwant\n %v", format(got), format(want))
+	}
+}
+
+func TestParseICalErrors(t *testing.T) {
+	event := func(lines ...string) string {
+		return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:1"}, lines...), "END:VEVENT", "END:VCALENDAR"), "\r\n")
+	}
+	tests := []struct {
+		name string
+		feed string
+	}{
+		{"rule without frequency", event("DTSTART:20200907T090000Z", "RRULE:COUNT=2")},
+		{"invalid interval", event("DTSTART:20200907T090000Z", "RRULE:FREQ=DAILY;INTERVAL=x")},
+		{"invalid time", event("DTSTART:2020-09-07T09:00:00Z")},
+		{"no start", event("SUMMARY:alex")},
+		{"end before start", event("DTSTART:20200907T090000Z", "DTEND:20200907T080000Z")},
+		{"invalid duration", event("DTSTART:20200907T090000Z", "DURATION:8H")},
+		{"invalid time zone offset", strings.Join([]string{"BEGIN:VCALENDAR", "BEGIN:VTIMEZONE", "TZID:Custom", "BEGIN:STANDARD",
+			"TZOFFSETTO:1 hour", "END:STANDARD", "END:VTIMEZONE", "BEGIN:VEVENT", "DTSTART;TZID=Custom:20200907T090000", "END:VEVENT", "END:VCALENDAR"}, "\r\n")},
+		{"invalid calendar time zone", "BEGIN:VCALENDAR\r\nX-WR-TIMEZONE:Mars/Olympus\r\nEND:VCALENDAR"},
+		{"unbalanced component", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR"},
+		{"unterminated calendar", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VEVENT"},
+		{"invalid line", "BEGIN:VCALENDAR\r\nnot a content line\r\nEND:VCALENDAR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseICal(strings.NewReader(tt.feed), nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseICalTimeZones(t *testing.T) {
+	feed := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"X-WR-TIMEZONE:Romance Standard Time",
+		"BEGIN:VTIMEZONE",
+		"TZID:W. Europe Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:16010101T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:16010101T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VTIMEZONE",
+		"TZID:/citadel.org/20190914_1/Asia/Tokyo",
+		"X-LIC-LOCATION:Asia/Tokyo",
+		"END:VTIMEZONE",
+		"BEGIN:VTIMEZONE",
+		"TZID:Customized Time Zone",
+		"BEGIN:STANDARD",
+		"DTSTART:16010101T000000",
+		"TZOFFSETFROM:+0530",
+		"TZOFFSETTO:+0530",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:windows",
+		"DTSTART;TZID=W. Europe Standard Time:20200907T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:location",
+		"DTSTART;TZID=/citadel.org/20190914_1/Asia/Tokyo:20200907T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:fixed",
+		"DTSTART;TZID=Customized Time Zone:20200907T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:calendar",
+		"DTSTART:20200907T090000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	calendar, err := ParseICal(strings.NewReader(feed), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range calendar.Events {
+		got = append(got, fmt.Sprintf("%s %s", e.UID, e.Start.UTC().Format(time.RFC3339)))
+	}
+	want := []string{
+		"windows 2020-09-07T07:00:00Z",
+		"location 2020-09-07T00:00:00Z",
+		"fixed 2020-09-07T03:30:00Z",
+		"calendar 2020-09-07T07:00:00Z",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("events are\n %v\nwant\n %v", got, want)
+	}
+}
+
+func TestParseICalNominalDuration(t *testing.T) {
+	feed := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:nominal",
+		"DTSTART;TZID=Europe/Berlin:20200328T090000",
+		"DURATION:P1D",
+		"RRULE:FREQ=DAILY;COUNT=2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:exact",
+		"DTSTART;TZID=Europe/Berlin:20200328T090000",
+		"DURATION:PT24H",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	calendar, err := ParseICal(strings.NewReader(feed), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// DST starts in Berlin on 2020-03-29, so the day is 23 hours long
+	occurrences, err := calendar.Occurrences(Window{
+		Start: time.Date(2020, 3, 28, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, o := range occurrences {
+		got = append(got, fmt.Sprintf("%s %s %s", o.Event.UID, o.Start.UTC().Format(time.RFC3339), o.End.UTC().Format(time.RFC3339)))
+	}
+	want := []string{
+		"nominal 2020-03-28T08:00:00Z 2020-03-29T07:00:00Z",
+		"exact 2020-03-28T08:00:00Z 2020-03-29T08:00:00Z",
+		"nominal 2020-03-29T07:00:00Z 2020-03-30T07:00:00Z",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("occurrences are\n %v\nwant\n %v", got, want)
+	}
+	if end := calendar.Events[0].End.UTC(); !end.Equal(time.Date(2020, 3, 29, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("nominal event ends at %s", end)
+	}
+}
+
+func TestParseICalSkipped(t *testing.T) {
+	feed := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTIMEZONE",
+		"TZID:Custom Daylight Time",
+		"BEGIN:DAYLIGHT",
+		"TZOFFSETTO:+0200",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:hourly",
+		"DTSTART:20200907T090000Z",
+		"RRULE:FREQ=HOURLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:setpos",
+		"DTSTART:20200907T090000Z",
+		"RRULE:FREQ=MONTHLY;BYSETPOS=-1;BYDAY=MO",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:mars",
+		"DTSTART;TZID=Mars/Olympus:20200907T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:daylight",
+		"DTSTART;TZID=Custom Daylight Time:20200907T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:supported",
+		"DTSTART:20200907T090000Z",
+		"RRULE:FREQ=DAILY",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	calendar, err := ParseICal(strings.NewReader(feed), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calendar.Events) != 1 || calendar.Events[0].UID != "supported" {
+		t.Errorf("parsed %d events, want only the supported one", len(calendar.Events))
+	}
+	want := []string{
+		`skipped iCal event hourly: unsupported RRULE frequency "HOURLY"`,
+		`skipped iCal event setpos: unsupported RRULE part BYSETPOS`,
+		`skipped iCal event mars: time zone of DTSTART: unknown time zone "Mars/Olympus"`,
+		`skipped iCal event daylight: time zone of DTSTART: unsupported time zone definition "Custom Daylight Time"`,
+	}
+	if !reflect.DeepEqual(want, calendar.Warnings) {
+		t.Errorf("warnings are\n %q\nwant\n %q", calendar.Warnings, want)
+	}
+}
+
+func TestParseICalLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		name   string
+		params map[string]string
+		value  string
+		err    bool
+	}{
+		{line: "SUMMARY:alex", name: "SUMMARY", params: map[string]string{}, value: "alex"},
+		{line: "dtstart;tzid=Europe/Berlin:20200907T090000", name: "DTSTART", params: map[string]string{"TZID": "Europe/Berlin"}, value: "20200907T090000"},
+		{line: `ATTENDEE;CN="Bob: Jr.; Ops";ROLE=REQ-PARTICIPANT:mailto:bob@example.com`, name: "ATTENDEE", params: map[string]string{"CN": "Bob: Jr.; Ops", "ROLE": "REQ-PARTICIPANT"}, value: "mailto:bob@example.com"},
+		{line: "ATTENDEE;DELEGATED-TO=a,b:mailto:c@example.com", name: "ATTENDEE", params: map[string]string{"DELEGATED-TO": "a"}, value: "mailto:c@example.com"},
+		{line: "DESCRIPTION:", name: "DESCRIPTION", params: map[string]string{}, value: ""},
+		{line: "no value", err: true},
+		{line: ":value", err: true},
+		{line: `ATTENDEE;CN="Bob:mailto:bob@example.com`, err: true},
+		{line: "DTSTART;TZID", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			prop, err := parseICalLine(tt.line)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got %+v", prop)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prop.name != tt.name || prop.value != tt.value || !reflect.DeepEqual(prop.params, tt.params) {
+				t.Errorf("parsed %+v, want %s %v %q", prop, tt.name, tt.params, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  icalDuration
+		err   bool
+	}{
+		{value: "PT8H", want: icalDuration{exact: 8 * time.Hour}},
+		{value: "P1DT12H30M15S", want: icalDuration{days: 1, exact: 12*time.Hour + 30*time.Minute + 15*time.Second}},
+		{value: "P2W", want: icalDuration{days: 14}},
+		{value: "-P1DT15M", want: icalDuration{days: -1, exact: -15 * time.Minute}},
+		{value: "+P1D", want: icalDuration{days: 1}},
+		{value: "8H", err: true},
+		{value: "P", err: true},
+		{value: "PT8", err: true},
+		{value: "P1H", err: true},
+		{value: "P1T2H", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseICalDuration(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parsed %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestICalUnescape(t *testing.T) {
+	for _, s := range []string{"plain", "a,b;c\\d\ne", `trailing\`} {
+		if got := icalUnescape(icalEscape(s)); got != s {
+			t.Errorf("unescaped %q, want %q", got, s)
+		}
+	}
+}
+
+func TestGetICalCalendar(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/schedules/SICAL/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"id": "SICAL", "type": "ical", "name": "External", "ical_url": "%s/feeds/on-call.ics", "on_call_now": []}`, server.URL)
+	})
+	mux.HandleFunc("/feeds/on-call.ics", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization is %q, want none", got)
+		}
+		fmt.Fprint(w, testICalFeed)
+	})
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserBody)
+	})
+
+	calendar, err := client.Schedules.GetICalCalendar("SICAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := calendar.WhoIsOnCall(time.Date(2020, 9, 8, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"U4DNY931HHJS5"}; !reflect.DeepEqual(want, users) {
+		t.Errorf("on call are %v, want %v", users, want)
+	}
+}
@@ -2,8 +2,8 @@ package amixr
 
 import (
 	"fmt"
-	"log"
 	"net/http"
+	"time"
 )
 
 // Handles requests to integration endpoint
@@ -29,13 +29,22 @@ type PaginatedIntegrationsResponse struct {
 }
 
 type Integration struct {
-	ID             string     `json:"id"`
-	Name           string     `json:"name"`
-	Link           string     `json:"link"`
-	IncidentsCount int        `json:"incidents_count"`
-	Type           string     `json:"type"`
-	DefaultRouteId string     `json:"default_route_id"`
-	Templates      *Templates `json:"templates"`
+	ID                   string     `json:"id"`
+	Name                 string     `json:"name"`
+	Link                 string     `json:"link"`
+	IncidentsCount       int        `json:"incidents_count"`
+	Type                 string     `json:"type"`
+	DefaultRouteId       string     `json:"default_route_id"`
+	Templates            *Templates `json:"templates"`
+	MaintenanceMode      *string    `json:"maintenance_mode"`
+	MaintenanceStartedAt *string    `json:"maintenance_started_at"`
+	MaintenanceEndAt     *string    `json:"maintenance_end_at"`
+}
+
+// MaintenanceDuration returns duration of current maintenance or debug mode.
+// ok is false if integration is not in maintenance.
+func (integration *Integration) MaintenanceDuration() (duration time.Duration, ok bool, err error) {
+	return maintenanceDuration(integration.MaintenanceStartedAt, integration.MaintenanceEndAt)
 }
 
 type Templates struct {
@@ -57,10 +66,10 @@ type ListIntegrationOptions struct {
 // ListIntegrations gets all integrations for authorized team
 //
 // http://api-docs.amixr.io/#list-integrations
-func (service *IntegrationService) ListIntegrations(opt *ListIntegrationOptions) (*PaginatedIntegrationsResponse, *http.Response, error) {
+func (service *IntegrationService) ListIntegrations(opt *ListIntegrationOptions, options ...RequestOption) (*PaginatedIntegrationsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s/", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -74,16 +83,57 @@ func (service *IntegrationService) ListIntegrations(opt *ListIntegrationOptions)
 	return integrations, resp, err
 }
 
+// IntegrationIterator iterates over integrations of all pages.
+// Use IntegrationService.Iter to create one.
+type IntegrationIterator struct {
+	pager
+	items []*Integration
+}
+
+// Iter returns iterator over all integrations matching opt, following pagination links
+func (service *IntegrationService) Iter(opt *ListIntegrationOptions, options ...RequestOption) *IntegrationIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &IntegrationIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedIntegrationsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Integrations
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current integration
+func (it *IntegrationIterator) Value() *Integration {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllIntegrations gets integrations of all pages
+func (service *IntegrationService) ListAllIntegrations(opt *ListIntegrationOptions, options ...RequestOption) ([]*Integration, error) {
+	var integrations []*Integration
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		integrations = append(integrations, it.Value())
+	}
+	return integrations, it.Err()
+}
+
 type GetIntegrationOptions struct {
 }
 
 // Get integration by given id
 //
 // http://api-docs.amixr.io/#get-integration
-func (service *IntegrationService) GetIntegration(id string, opt *GetIntegrationOptions) (*Integration, *http.Response, error) {
+func (service *IntegrationService) GetIntegration(id string, opt *GetIntegrationOptions, options ...RequestOption) (*Integration, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -106,11 +156,11 @@ type CreateIntegrationOptions struct {
 // Create integration with given name and type
 //
 // http://api-docs.amixr.io/#create-integration
-func (service *IntegrationService) CreateIntegration(opt *CreateIntegrationOptions) (*Integration, *http.Response, error) {
-	log.Printf("[DEBUG] create amixr integration")
+func (service *IntegrationService) CreateIntegration(opt *CreateIntegrationOptions, options ...RequestOption) (*Integration, *http.Response, error) {
+	service.client.logger.Debug("create amixr integration")
 	u := fmt.Sprintf("%s/", service.url)
 
-	req, err := service.client.NewRequest("POST", u, opt)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -125,17 +175,17 @@ func (service *IntegrationService) CreateIntegration(opt *CreateIntegrationOptio
 }
 
 type UpdateIntegrationOptions struct {
-	Name      string     `json:"name, omitempty"`
+	Name      string     `json:"name"`
 	Templates *Templates `json:"templates,omitempty"`
 }
 
 // Updates integration with new templates and/or name. At least one field in template is required
 //
 // http://api-docs.amixr.io/#update-integration
-func (service *IntegrationService) UpdateIntegration(id string, opt *UpdateIntegrationOptions) (*Integration, *http.Response, error) {
+func (service *IntegrationService) UpdateIntegration(id string, opt *UpdateIntegrationOptions, options ...RequestOption) (*Integration, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("PUT", u, opt)
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -155,11 +205,40 @@ type DeleteIntegrationOptions struct {
 // Deletes integration
 //
 // http://api-docs.amixr.io/#delete-integration
-func (service *IntegrationService) DeleteIntegration(id string, opt *DeleteIntegrationOptions) (*http.Response, error) {
+func (service *IntegrationService) DeleteIntegration(id string, opt *DeleteIntegrationOptions, options ...RequestOption) (*http.Response, error) {
 
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("DELETE", u, opt)
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
+
+// StartMaintenance puts integration into maintenance or debug mode for given duration
+func (service *IntegrationService) StartMaintenance(id string, opt *StartMaintenanceOptions, options ...RequestOption) (*http.Response, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/%s/maintenance_start/", service.url, id)
+
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
+
+// StopMaintenance returns integration from maintenance or debug mode
+func (service *IntegrationService) StopMaintenance(id string, options ...RequestOption) (*http.Response, error) {
+	u := fmt.Sprintf("%s/%s/maintenance_stop/", service.url, id)
+
+	req, err := service.client.NewRequest("POST", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -167,3 +246,23 @@ func (service *IntegrationService) DeleteIntegration(id string, opt *DeleteInteg
 	resp, err := service.client.Do(req, nil)
 	return resp, err
 }
+
+// WithMaintenance runs fn while integration is in maintenance or debug mode.
+// Maintenance is stopped when fn returns, even if it fails or panics. Stop is retried with a context
+// of its own, see MaintenanceStopTimeout, and its failure is returned as MaintenanceStopError.
+func (service *IntegrationService) WithMaintenance(id string, opt *StartMaintenanceOptions, fn func() error, options ...RequestOption) (err error) {
+	if _, err := service.StartMaintenance(id, opt, options...); err != nil {
+		return err
+	}
+	defer func() {
+		stopErr := stopMaintenance(func(options ...RequestOption) error {
+			_, err := service.StopMaintenance(id, options...)
+			return err
+		}, options)
+		if stopErr != nil {
+			err = &MaintenanceStopError{Err: err, StopErr: stopErr}
+		}
+	}()
+
+	return fn()
+}
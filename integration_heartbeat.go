@@ -0,0 +1,228 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Handles requests to integration heartbeat endpoint
+// Heartbeat alerts when integration has not been pinged for longer than timeout,
+// which is handy for monitoring cron jobs.
+// Use NewIntegrationHeartbeatService instead of direct creation IntegrationHeartbeatService
+type IntegrationHeartbeatService struct {
+	client *Client
+	url    string
+}
+
+// NewIntegrationHeartbeatService creates IntegrationHeartbeatService with defined url
+func NewIntegrationHeartbeatService(client *Client) *IntegrationHeartbeatService {
+	integrationHeartbeatService := IntegrationHeartbeatService{}
+	integrationHeartbeatService.client = client
+	integrationHeartbeatService.url = "integration_heartbeats"
+	return &integrationHeartbeatService
+}
+
+type PaginatedIntegrationHeartbeatsResponse struct {
+	PaginatedResponse
+	IntegrationHeartbeats []*IntegrationHeartbeat `json:"results"`
+}
+
+type IntegrationHeartbeat struct {
+	ID            string `json:"id"`
+	IntegrationId string `json:"integration_id"`
+	// Timeout in seconds after the last heartbeat when alert is raised
+	Timeout           int     `json:"timeout"`
+	LastHeartbeatTime *string `json:"last_heartbeat_time"`
+	// Status is true while heartbeats arrive in time
+	Status bool `json:"status"`
+	// Link is the URL to be pinged by monitored job
+	Link string `json:"link"`
+}
+
+type ListIntegrationHeartbeatOptions struct {
+	ListOptions
+	IntegrationId string `url:"integration_id,omitempty" json:"integration_id,omitempty"`
+}
+
+// ListIntegrationHeartbeats gets integration heartbeats for authorized team
+func (service *IntegrationHeartbeatService) ListIntegrationHeartbeats(opt *ListIntegrationHeartbeatOptions, options ...RequestOption) (*PaginatedIntegrationHeartbeatsResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var integrationHeartbeats *PaginatedIntegrationHeartbeatsResponse
+	resp, err := service.client.Do(req, &integrationHeartbeats)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return integrationHeartbeats, resp, err
+}
+
+// IntegrationHeartbeatIterator iterates over integration heartbeats of all pages.
+// Use IntegrationHeartbeatService.Iter to create one.
+type IntegrationHeartbeatIterator struct {
+	pager
+	items []*IntegrationHeartbeat
+}
+
+// Iter returns iterator over all integration heartbeats matching opt, following pagination links
+func (service *IntegrationHeartbeatService) Iter(opt *ListIntegrationHeartbeatOptions, options ...RequestOption) *IntegrationHeartbeatIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &IntegrationHeartbeatIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedIntegrationHeartbeatsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.IntegrationHeartbeats
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current integrationHeartbeat
+func (it *IntegrationHeartbeatIterator) Value() *IntegrationHeartbeat {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllIntegrationHeartbeats gets integration heartbeats of all pages
+func (service *IntegrationHeartbeatService) ListAllIntegrationHeartbeats(opt *ListIntegrationHeartbeatOptions, options ...RequestOption) ([]*IntegrationHeartbeat, error) {
+	var integrationHeartbeats []*IntegrationHeartbeat
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		integrationHeartbeats = append(integrationHeartbeats, it.Value())
+	}
+	return integrationHeartbeats, it.Err()
+}
+
+type GetIntegrationHeartbeatOptions struct {
+}
+
+// Get integration heartbeat by given id
+func (service *IntegrationHeartbeatService) GetIntegrationHeartbeat(id string, opt *GetIntegrationHeartbeatOptions, options ...RequestOption) (*IntegrationHeartbeat, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	integrationHeartbeat := new(IntegrationHeartbeat)
+	resp, err := service.client.Do(req, integrationHeartbeat)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return integrationHeartbeat, resp, err
+}
+
+type CreateIntegrationHeartbeatOptions struct {
+	IntegrationId string `json:"integration_id"`
+	Timeout       int    `json:"timeout"`
+}
+
+// Create heartbeat for integration
+func (service *IntegrationHeartbeatService) CreateIntegrationHeartbeat(opt *CreateIntegrationHeartbeatOptions, options ...RequestOption) (*IntegrationHeartbeat, *http.Response, error) {
+	service.client.logger.Debug("create amixr integration heartbeat")
+	u := fmt.Sprintf("%s/", service.url)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	integrationHeartbeat := new(IntegrationHeartbeat)
+
+	resp, err := service.client.Do(req, integrationHeartbeat)
+
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return integrationHeartbeat, resp, err
+}
+
+type UpdateIntegrationHeartbeatOptions struct {
+	Timeout int `json:"timeout"`
+}
+
+// Updates timeout of integration heartbeat
+func (service *IntegrationHeartbeatService) UpdateIntegrationHeartbeat(id string, opt *UpdateIntegrationHeartbeatOptions, options ...RequestOption) (*IntegrationHeartbeat, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	integrationHeartbeat := new(IntegrationHeartbeat)
+	resp, err := service.client.Do(req, integrationHeartbeat)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return integrationHeartbeat, resp, err
+}
+
+type DeleteIntegrationHeartbeatOptions struct {
+}
+
+// Deletes integration heartbeat
+func (service *IntegrationHeartbeatService) DeleteIntegrationHeartbeat(id string, opt *DeleteIntegrationHeartbeatOptions, options ...RequestOption) (*http.Response, error) {
+
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
+
+// Ping sends a heartbeat to given IntegrationHeartbeat.Link.
+// The link authorizes itself, so API token is not sent along.
+func (service *IntegrationHeartbeatService) Ping(link string, options ...RequestOption) (*http.Response, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid heartbeat link %q: %w", link, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid heartbeat link %q: absolute http(s) URL required", link)
+	}
+
+	req, err := retryablehttp.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if service.client.userAgent != "" {
+		req.Header.Set("User-Agent", service.client.userAgent)
+	}
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := service.client.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return resp, CheckResponse(resp)
+}
@@ -0,0 +1,177 @@
+package amixr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var testLastHeartbeatTime = "2020-09-04T13:00:00Z"
+
+var testIntegrationHeartbeat = &IntegrationHeartbeat{
+	ID:                "B4PZ3DNN5QJ6A",
+	IntegrationId:     "CFRPV98RPR1U8",
+	Timeout:           3600,
+	LastHeartbeatTime: &testLastHeartbeatTime,
+	Status:            true,
+	Link:              "https://app.amixr.io/integrations/v1/grafana/mReAoNwDm0eMwKo1mTeTwYo/heartbeat/",
+}
+
+var testIntegrationHeartbeatBody = `{
+	"id": "B4PZ3DNN5QJ6A",
+	"integration_id": "CFRPV98RPR1U8",
+	"timeout": 3600,
+	"last_heartbeat_time": "2020-09-04T13:00:00Z",
+	"status": true,
+	"link": "https://app.amixr.io/integrations/v1/grafana/mReAoNwDm0eMwKo1mTeTwYo/heartbeat/"
+}`
+
+func TestCreateIntegrationHeartbeat(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integration_heartbeats/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		fmt.Fprint(w, testIntegrationHeartbeatBody)
+	})
+
+	createOptions := &CreateIntegrationHeartbeatOptions{
+		IntegrationId: "CFRPV98RPR1U8",
+		Timeout:       3600,
+	}
+	heartbeat, _, err := client.IntegrationHeartbeats.CreateIntegrationHeartbeat(createOptions)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testIntegrationHeartbeat
+
+	if !reflect.DeepEqual(want, heartbeat) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", heartbeat, want)
+	}
+}
+
+func TestListIntegrationHeartbeats(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integration_heartbeats/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.URL.Query().Get("integration_id"); got != "CFRPV98RPR1U8" {
+			t.Errorf("integration_id is %q, want %q", got, "CFRPV98RPR1U8")
+		}
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testIntegrationHeartbeatBody))
+	})
+
+	heartbeats, _, err := client.IntegrationHeartbeats.ListIntegrationHeartbeats(&ListIntegrationHeartbeatOptions{IntegrationId: "CFRPV98RPR1U8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PaginatedIntegrationHeartbeatsResponse{
+		PaginatedResponse: PaginatedResponse{
+			Count:    1,
+			Next:     nil,
+			Previous: nil,
+		},
+		IntegrationHeartbeats: []*IntegrationHeartbeat{
+			testIntegrationHeartbeat,
+		},
+	}
+	if !reflect.DeepEqual(want, heartbeats) {
+		t.Errorf("returned\n %+v, \nwant\n %+v", heartbeats, want)
+	}
+}
+
+func TestGetIntegrationHeartbeat(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integration_heartbeats/B4PZ3DNN5QJ6A/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testIntegrationHeartbeatBody)
+	})
+
+	heartbeat, _, err := client.IntegrationHeartbeats.GetIntegrationHeartbeat("B4PZ3DNN5QJ6A", &GetIntegrationHeartbeatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testIntegrationHeartbeat
+
+	if !reflect.DeepEqual(want, heartbeat) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", heartbeat, want)
+	}
+}
+
+func TestUpdateIntegrationHeartbeat(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integration_heartbeats/B4PZ3DNN5QJ6A/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "PUT")
+		fmt.Fprint(w, testIntegrationHeartbeatBody)
+	})
+
+	heartbeat, _, err := client.IntegrationHeartbeats.UpdateIntegrationHeartbeat("B4PZ3DNN5QJ6A", &UpdateIntegrationHeartbeatOptions{Timeout: 3600})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testIntegrationHeartbeat
+
+	if !reflect.DeepEqual(want, heartbeat) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", heartbeat, want)
+	}
+}
+
+func TestDeleteIntegrationHeartbeat(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integration_heartbeats/B4PZ3DNN5QJ6A/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "DELETE")
+	})
+
+	_, err := client.IntegrationHeartbeats.DeleteIntegrationHeartbeat("B4PZ3DNN5QJ6A", &DeleteIntegrationHeartbeatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPingIntegrationHeartbeat(t *testing.T) {
+	pings := 0
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if r.URL.Path != "/integrations/v1/grafana/mReAoNwDm0eMwKo1mTeTwYo/heartbeat/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("token must not be sent to heartbeat link, got %q", got)
+		}
+		pings++
+	}))
+	defer stub.Close()
+
+	_, server, client := setup(t)
+	defer teardown(server)
+
+	if _, err := client.IntegrationHeartbeats.Ping(stub.URL + "/integrations/v1/grafana/mReAoNwDm0eMwKo1mTeTwYo/heartbeat/"); err != nil {
+		t.Fatal(err)
+	}
+	if pings != 1 {
+		t.Errorf("stub pinged %d times, want 1", pings)
+	}
+
+	if _, err := client.IntegrationHeartbeats.Ping(stub.URL + "/unknown/"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.IntegrationHeartbeats.Ping("/relative/heartbeat/"); err == nil {
+		t.Error("expected error for relative link")
+	}
+}
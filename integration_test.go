@@ -1,10 +1,14 @@
 package amixr
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var key = "key"
@@ -142,3 +146,123 @@ func TestGetIntegration(t *testing.T) {
 		t.Errorf("returned\n %+v\n want\n %+v\n", integration, want)
 	}
 }
+
+func TestIntegrationMaintenance(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	var calls []string
+	mux.HandleFunc("/api/v1/integrations/CFRPV98RPR1U8/maintenance_start/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		var body StartMaintenanceOptions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Mode != MaintenanceModeDebug || body.Duration != 3600 {
+			t.Errorf("unexpected body %+v", body)
+		}
+		calls = append(calls, "start")
+	})
+	mux.HandleFunc("/api/v1/integrations/CFRPV98RPR1U8/maintenance_stop/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		calls = append(calls, "stop")
+	})
+
+	options := &StartMaintenanceOptions{Mode: MaintenanceModeDebug, Duration: 3600}
+	deployErr := errors.New("deploy failed")
+	err := client.Integrations.WithMaintenance("CFRPV98RPR1U8", options, func() error {
+		calls = append(calls, "deploy")
+		return deployErr
+	})
+	if err != deployErr {
+		t.Errorf("expected deploy error, got %v", err)
+	}
+
+	want := []string{"start", "deploy", "stop"}
+	if !reflect.DeepEqual(want, calls) {
+		t.Errorf("called %v, want %v", calls, want)
+	}
+}
+
+func TestIntegrationMaintenanceStop(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	stopStatus := http.StatusOK
+	var stops int
+	mux.HandleFunc("/api/v1/integrations/CFRPV98RPR1U8/maintenance_start/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+	})
+	mux.HandleFunc("/api/v1/integrations/CFRPV98RPR1U8/maintenance_stop/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		stops++
+		w.WriteHeader(stopStatus)
+		fmt.Fprint(w, `{"detail": "stop failed"}`)
+	})
+	options := &StartMaintenanceOptions{Mode: MaintenanceModeMaintenance, Duration: 3600}
+
+	// maintenance is stopped even when context of the caller is done
+	ctx, cancel := context.WithCancel(context.Background())
+	err := client.Integrations.WithMaintenance("CFRPV98RPR1U8", options, func() error {
+		cancel()
+		return ctx.Err()
+	}, WithContext(ctx))
+	if err != context.Canceled {
+		t.Errorf("expected context error, got %v", err)
+	}
+	if stops != 1 {
+		t.Errorf("stopped %d times, want 1", stops)
+	}
+
+	stopStatus = http.StatusBadRequest
+	deployErr := errors.New("deploy failed")
+	err = client.Integrations.WithMaintenance("CFRPV98RPR1U8", options, func() error {
+		return deployErr
+	})
+	var stopErr *MaintenanceStopError
+	if !errors.As(err, &stopErr) || stopErr.StopErr == nil {
+		t.Fatalf("expected maintenance stop error, got %v", err)
+	}
+	if !errors.Is(err, deployErr) {
+		t.Errorf("error %v doesn't match deploy error", err)
+	}
+}
+
+func TestStartMaintenanceValidation(t *testing.T) {
+	_, server, client := setup(t)
+	defer teardown(server)
+
+	invalid := []*StartMaintenanceOptions{
+		nil,
+		{Mode: "silence", Duration: 3600},
+		{Mode: MaintenanceModeMaintenance, Duration: 0},
+	}
+	for _, options := range invalid {
+		if _, err := client.Integrations.StartMaintenance("CFRPV98RPR1U8", options); err == nil {
+			t.Errorf("expected error for %+v", options)
+		}
+	}
+}
+
+func TestIntegrationMaintenanceDuration(t *testing.T) {
+	mode := MaintenanceModeMaintenance
+	startedAt := "2020-09-04T13:00:00Z"
+	endAt := "2020-09-04T14:30:00Z"
+	integration := &Integration{
+		MaintenanceMode:      &mode,
+		MaintenanceStartedAt: &startedAt,
+		MaintenanceEndAt:     &endAt,
+	}
+
+	duration, ok, err := integration.MaintenanceDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || duration != 90*time.Minute {
+		t.Errorf("duration is %s (%v), want 1h30m", duration, ok)
+	}
+
+	if _, ok, _ := testIntegration.MaintenanceDuration(); ok {
+		t.Error("integration without maintenance should report no duration")
+	}
+}
@@ -0,0 +1,102 @@
+package amixr
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Logger is a leveled logger used by Client. keysAndValues are alternating
+// field names and values, e.g. "method", "GET", "status", 200.
+//
+// Logger has the same method set as retryablehttp.LeveledLogger, so any
+// LeveledLogger can be passed to WithLogger as is, and Client hands its Logger
+// to the underlying retryablehttp.Client to log retries.
+type Logger interface {
+	Error(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Debug(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+var _ retryablehttp.LeveledLogger = Logger(nil)
+
+// noopLogger is used by default, client is silent unless a logger is configured
+type noopLogger struct{}
+
+func (noopLogger) Error(msg string, keysAndValues ...interface{}) {}
+func (noopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (noopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (noopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// stdLogger adapts standard library logger to Logger
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+// NewStdLogger returns Logger writing messages of given level and above to standard library logger
+// in format "[LEVEL] message key=value ...". Nil logger writes to the output of the standard logger.
+func NewStdLogger(logger *log.Logger, level LogLevel) Logger {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return &stdLogger{logger: logger, level: level}
+}
+
+func (l *stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.print(LogLevelError, msg, keysAndValues)
+}
+
+func (l *stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.print(LogLevelInfo, msg, keysAndValues)
+}
+
+func (l *stdLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.print(LogLevelDebug, msg, keysAndValues)
+}
+
+func (l *stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.print(LogLevelWarn, msg, keysAndValues)
+}
+
+func (l *stdLogger) print(level LogLevel, msg string, keysAndValues []interface{}) {
+	if level < l.level {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v=MISSING", keysAndValues[i])
+		}
+	}
+	l.logger.Print(b.String())
+}
@@ -0,0 +1,121 @@
+package amixr
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedLog struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	mu   sync.Mutex
+	logs []recordedLog
+}
+
+func (l *recordingLogger) record(level, msg string, keysAndValues []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	l.logs = append(l.logs, recordedLog{level, msg, fields})
+}
+
+func (l *recordingLogger) Error(msg string, kv ...interface{}) { l.record("error", msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...interface{})  { l.record("info", msg, kv) }
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) { l.record("debug", msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record("warn", msg, kv) }
+
+func (l *recordingLogger) find(msg string) []recordedLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var found []recordedLog
+	for _, entry := range l.logs {
+		if entry.msg == msg {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), LogLevelInfo)
+
+	logger.Debug("hidden", "key", "value")
+	logger.Info("request completed", "method", "GET", "status", 200)
+	logger.Error("request failed", "dangling")
+
+	want := "[INFO] request completed method=GET status=200\n[ERROR] request failed dangling=MISSING\n"
+	if buf.String() != want {
+		t.Errorf("logged\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestClientLogsRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, testUserBody)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client, err := NewClient("token",
+		WithBaseURL(server.URL),
+		WithLogger(logger),
+		WithRetryPolicy(1, time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.Users.GetUser("U4DNY931HHJS5", &GetUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := logger.find("sending request")
+	if len(attempts) != 2 {
+		t.Fatalf("logged %d attempts, want 2", len(attempts))
+	}
+	if attempts[1].fields["attempt"] != 1 || attempts[1].fields["path"] != "/api/v1/users/U4DNY931HHJS5/" {
+		t.Errorf("unexpected attempt fields %+v", attempts[1].fields)
+	}
+
+	if len(logger.find("rate limited")) != 1 {
+		t.Error("expected rate limited to be logged")
+	}
+
+	completed := logger.find("request completed")
+	if len(completed) != 1 {
+		t.Fatalf("logged %d completed requests, want 1", len(completed))
+	}
+	fields := completed[0].fields
+	if fields["method"] != "GET" || fields["status"] != 200 {
+		t.Errorf("unexpected completed fields %+v", fields)
+	}
+	if _, ok := fields["latency"].(time.Duration); !ok {
+		t.Errorf("latency is %T, want time.Duration", fields["latency"])
+	}
+}
+
+func TestWithLoggerNil(t *testing.T) {
+	if _, err := NewClient("token", WithLogger(nil)); err == nil {
+		t.Error("expected error for nil logger")
+	}
+}
@@ -0,0 +1,101 @@
+package amixr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Maintenance modes. Alerts are suppressed in maintenance mode, while in debug mode
+// they are processed but notifications are not sent.
+const (
+	MaintenanceModeMaintenance = "maintenance"
+	MaintenanceModeDebug       = "debug"
+)
+
+// MaintenanceStopTimeout bounds each attempt of WithMaintenance to stop maintenance.
+// Stop doesn't use context of the caller, which is often done by the time fn returns.
+const MaintenanceStopTimeout = 30 * time.Second
+
+// maintenanceStopAttempts is how many times WithMaintenance tries to stop maintenance
+const maintenanceStopAttempts = 3
+
+// MaintenanceStopError is returned by WithMaintenance when maintenance couldn't be stopped.
+// errors.Is and errors.As match the error of fn, if any.
+type MaintenanceStopError struct {
+	// Err is the error returned by fn
+	Err     error
+	StopErr error
+}
+
+func (e *MaintenanceStopError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("stop maintenance: %v", e.StopErr)
+	}
+	return fmt.Sprintf("%v; stop maintenance: %v", e.Err, e.StopErr)
+}
+
+func (e *MaintenanceStopError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	return e.StopErr
+}
+
+type StartMaintenanceOptions struct {
+	Mode string `json:"mode"`
+	// Duration of maintenance in seconds, it is stopped automatically afterwards
+	Duration int `json:"duration"`
+}
+
+func (opt *StartMaintenanceOptions) validate() error {
+	if opt == nil {
+		return fmt.Errorf("maintenance options required")
+	}
+	if opt.Mode != MaintenanceModeMaintenance && opt.Mode != MaintenanceModeDebug {
+		return fmt.Errorf("unknown maintenance mode %q", opt.Mode)
+	}
+	if opt.Duration <= 0 {
+		return fmt.Errorf("maintenance duration must be positive, got %d", opt.Duration)
+	}
+	return nil
+}
+
+func maintenanceDuration(startedAt, endAt *string) (time.Duration, bool, error) {
+	if startedAt == nil || endAt == nil {
+		return 0, false, nil
+	}
+	start, err := time.Parse(time.RFC3339, *startedAt)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid maintenance start %q: %w", *startedAt, err)
+	}
+	end, err := time.Parse(time.RFC3339, *endAt)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid maintenance end %q: %w", *endAt, err)
+	}
+	return end.Sub(start), true, nil
+}
+
+// stopMaintenance calls stop with a fresh context of its own timeout, retrying failures
+// other than client errors
+func stopMaintenance(stop func(options ...RequestOption) error, options []RequestOption) error {
+	var err error
+	for attempt := 0; attempt < maintenanceStopAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), MaintenanceStopTimeout)
+		err = stop(append(append([]RequestOption{}, options...), WithContext(ctx))...)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		var apiErr *ErrorResponse
+		if errors.As(err, &apiErr) && apiErr.Response != nil && apiErr.Response.StatusCode < http.StatusInternalServerError {
+			return err
+		}
+	}
+	return err
+}
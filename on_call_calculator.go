@@ -0,0 +1,364 @@
+package amixr
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ShiftOccurrence is a single occurrence of an on-call shift
+type ShiftOccurrence struct {
+	// Shift is the on-call shift the occurrence belongs to, nil if it comes from an iCal feed
+	Shift *OnCallShift
+	// Event is the iCal event the occurrence belongs to, nil for on-call shifts
+	Event    *ICalEvent
+	Start    time.Time
+	End      time.Time
+	Users    []string
+	Level    int
+	Override bool
+}
+
+// OnCallInterval is a period of time with the same users on call
+type OnCallInterval struct {
+	Start time.Time
+	End   time.Time
+	// Users are sorted ids of users on call
+	Users []string
+	// Occurrences are the shift occurrences which put Users on call, ordered by start
+	Occurrences []*ShiftOccurrence
+}
+
+// OnCallSource resolves on-call shifts of a schedule into concrete time.
+// It is implemented by OnCallCalculator for calendar schedules and ICalCalendar for ical ones.
+type OnCallSource interface {
+	// Location returns the time zone of the schedule
+	Location() *time.Location
+	// Occurrences returns shift occurrences intersecting the window, ordered by start
+	Occurrences(window Window) ([]*ShiftOccurrence, error)
+	// OnCallIntervals returns intervals within the window when someone is on call, ordered by start
+	OnCallIntervals(window Window) ([]*OnCallInterval, error)
+	// WhoIsOnCall returns sorted ids of users on call at given time
+	WhoIsOnCall(at time.Time) ([]string, error)
+}
+
+// OnCallCalculator resolves who is on call in a schedule from its on-call shifts
+// without asking amixr. Use NewOnCallCalculator to create one.
+//
+// Overrides take precedence over all other shifts, among regular shifts the ones
+// with higher level win. Users of the winning shifts which are on call at the same
+// time are all on call. Rolling users rotate on every recurrence period, e.g. each
+// week for a weekly shift, however many days of the week it has.
+type OnCallCalculator struct {
+	schedule *Schedule
+	location *time.Location
+	rules    []*shiftRule
+}
+
+type shiftRule struct {
+	shift      *OnCallShift
+	start      time.Time
+	duration   time.Duration
+	recurrence *recurrence
+}
+
+// NewOnCallCalculator validates on-call shifts of the schedule and creates calculator for them.
+// Shifts which belong to other schedules are ignored.
+func NewOnCallCalculator(schedule *Schedule, shifts []*OnCallShift) (*OnCallCalculator, error) {
+	if schedule == nil {
+		return nil, fmt.Errorf("schedule required")
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	calculator := &OnCallCalculator{schedule: schedule, location: loc}
+	for _, shift := range shifts {
+		if shift.ScheduleId != "" && shift.ScheduleId != schedule.ID {
+			continue
+		}
+		rule, err := newShiftRule(shift, loc)
+		if err != nil {
+			return nil, err
+		}
+		calculator.rules = append(calculator.rules, rule)
+	}
+	return calculator, nil
+}
+
+// GetOnCallCalculator gets the schedule with all its on-call shifts and creates calculator for them
+func (service *ScheduleService) GetOnCallCalculator(scheduleID string, options ...RequestOption) (*OnCallCalculator, error) {
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	shifts, err := service.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: schedule.ID}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return NewOnCallCalculator(schedule, shifts)
+}
+
+func newShiftRule(shift *OnCallShift, loc *time.Location) (*shiftRule, error) {
+	start, err := shift.StartTime(loc)
+	if err != nil {
+		return nil, err
+	}
+	if shift.Duration <= 0 {
+		return nil, fmt.Errorf("on-call shift %s has non-positive duration %d", shift.ID, shift.Duration)
+	}
+
+	r := &recurrence{interval: 1, weekStart: time.Monday}
+	switch shift.Type {
+	case OnCallShiftTypeSingleEvent, OnCallShiftTypeOverride:
+	case OnCallShiftTypeRecurrentEvent, OnCallShiftTypeRollingUsers:
+		if shift.Frequency != nil {
+			r.frequency = *shift.Frequency
+		}
+		if shift.Interval != nil {
+			r.interval = *shift.Interval
+		}
+		if shift.WeekStart != nil && *shift.WeekStart != "" {
+			if r.weekStart, err = parseWeekday(*shift.WeekStart); err != nil {
+				return nil, fmt.Errorf("invalid week start of on-call shift %s: %w", shift.ID, err)
+			}
+		}
+		if shift.ByDay != nil {
+			for _, day := range *shift.ByDay {
+				wd, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, fmt.Errorf("invalid by day of on-call shift %s: %w", shift.ID, err)
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		}
+		if shift.ByMonth != nil {
+			r.byMonth = *shift.ByMonth
+		}
+		if shift.ByMonthday != nil {
+			r.byMonthday = *shift.ByMonthday
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q of on-call shift %s", shift.Type, shift.ID)
+	}
+	if err := r.validate(); err != nil {
+		return nil, fmt.Errorf("invalid recurrence of on-call shift %s: %w", shift.ID, err)
+	}
+
+	return &shiftRule{
+		shift:      shift,
+		start:      start,
+		duration:   time.Duration(shift.Duration) * time.Second,
+		recurrence: r,
+	}, nil
+}
+
+func (rule *shiftRule) users(period int) []string {
+	shift := rule.shift
+	if shift.Type == OnCallShiftTypeRollingUsers {
+		if shift.RollingUsers == nil || len(*shift.RollingUsers) == 0 {
+			return nil
+		}
+		groups := *shift.RollingUsers
+		return groups[period%len(groups)]
+	}
+	if shift.Users == nil {
+		return nil
+	}
+	return *shift.Users
+}
+
+func (rule *shiftRule) occurrences(window Window) ([]*ShiftOccurrence, error) {
+	var occurrences []*ShiftOccurrence
+	err := rule.recurrence.expand(rule.start, window.End, func(start time.Time, period int) bool {
+		end := start.Add(rule.duration)
+		if window.Overlaps(start, end) {
+			occurrences = append(occurrences, &ShiftOccurrence{
+				Shift:    rule.shift,
+				Start:    start,
+				End:      end,
+				Users:    rule.users(period),
+				Level:    rule.shift.Level,
+				Override: rule.shift.Type == OnCallShiftTypeOverride,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("on-call shift %s: %w", rule.shift.ID, err)
+	}
+	return occurrences, nil
+}
+
+// Schedule returns the schedule the calculator was created for
+func (calculator *OnCallCalculator) Schedule() *Schedule {
+	return calculator.schedule
+}
+
+// Location returns the schedule time zone
+func (calculator *OnCallCalculator) Location() *time.Location {
+	return calculator.location
+}
+
+// Occurrences expands on-call shifts into occurrences intersecting the window, ordered by start.
+// Occurrences are not clipped to the window.
+func (calculator *OnCallCalculator) Occurrences(window Window) ([]*ShiftOccurrence, error) {
+	if err := window.validate(); err != nil {
+		return nil, err
+	}
+	var occurrences []*ShiftOccurrence
+	for _, rule := range calculator.rules {
+		ruleOccurrences, err := rule.occurrences(window)
+		if err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, ruleOccurrences...)
+	}
+	sortOccurrences(occurrences)
+	return occurrences, nil
+}
+
+// OnCallIntervals returns intervals within the window when someone is on call, ordered by start.
+// Adjacent intervals always differ in users, time nobody is on call is left out.
+func (calculator *OnCallCalculator) OnCallIntervals(window Window) ([]*OnCallInterval, error) {
+	occurrences, err := calculator.Occurrences(window)
+	if err != nil {
+		return nil, err
+	}
+	return resolveOnCallIntervals(occurrences, window), nil
+}
+
+// WhoIsOnCall returns sorted ids of users on call at given time
+func (calculator *OnCallCalculator) WhoIsOnCall(at time.Time) ([]string, error) {
+	return whoIsOnCall(calculator, at)
+}
+
+func whoIsOnCall(source OnCallSource, at time.Time) ([]string, error) {
+	intervals, err := source.OnCallIntervals(Window{Start: at, End: at.Add(time.Nanosecond)})
+	if err != nil {
+		return nil, err
+	}
+	if len(intervals) == 0 {
+		return nil, nil
+	}
+	return intervals[0].Users, nil
+}
+
+func sortOccurrences(occurrences []*ShiftOccurrence) {
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+}
+
+// resolveOnCallIntervals splits the window at occurrence bounds and picks the winning
+// occurrences for every part, merging adjacent parts with the same users
+func resolveOnCallIntervals(occurrences []*ShiftOccurrence, window Window) []*OnCallInterval {
+	bounds := []time.Time{window.Start, window.End}
+	for _, occurrence := range occurrences {
+		if window.Contains(occurrence.Start) {
+			bounds = append(bounds, occurrence.Start)
+		}
+		if window.Contains(occurrence.End) {
+			bounds = append(bounds, occurrence.End)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	var intervals []*OnCallInterval
+	for i := 0; i+1 < len(bounds); i++ {
+		start, end := bounds[i], bounds[i+1]
+		if !end.After(start) {
+			continue
+		}
+		winners := winningOccurrences(occurrences, start, end)
+		users := occurrenceUsers(winners)
+		if len(users) == 0 {
+			continue
+		}
+
+		if n := len(intervals); n > 0 && intervals[n-1].End.Equal(start) && equalStrings(intervals[n-1].Users, users) {
+			last := intervals[n-1]
+			last.End = end
+			for _, winner := range winners {
+				if !containsOccurrence(last.Occurrences, winner) {
+					last.Occurrences = append(last.Occurrences, winner)
+				}
+			}
+			continue
+		}
+		intervals = append(intervals, &OnCallInterval{Start: start, End: end, Users: users, Occurrences: winners})
+	}
+	return intervals
+}
+
+// winningOccurrences returns occurrences with users covering [start, end) which have the highest priority
+func winningOccurrences(occurrences []*ShiftOccurrence, start, end time.Time) []*ShiftOccurrence {
+	var winners []*ShiftOccurrence
+	for _, occurrence := range occurrences {
+		if len(occurrence.Users) == 0 || occurrence.Start.After(start) || occurrence.End.Before(end) {
+			continue
+		}
+		if len(winners) > 0 {
+			switch compareOccurrencePriority(occurrence, winners[0]) {
+			case -1:
+				continue
+			case 1:
+				winners = winners[:0]
+			}
+		}
+		winners = append(winners, occurrence)
+	}
+	return winners
+}
+
+func compareOccurrencePriority(a, b *ShiftOccurrence) int {
+	switch {
+	case a.Override != b.Override:
+		if a.Override {
+			return 1
+		}
+		return -1
+	case a.Level > b.Level:
+		return 1
+	case a.Level < b.Level:
+		return -1
+	}
+	return 0
+}
+
+func occurrenceUsers(occurrences []*ShiftOccurrence) []string {
+	seen := make(map[string]bool)
+	var users []string
+	for _, occurrence := range occurrences {
+		for _, user := range occurrence.Users {
+			if !seen[user] {
+				seen[user] = true
+				users = append(users, user)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+func containsOccurrence(occurrences []*ShiftOccurrence, occurrence *ShiftOccurrence) bool {
+	for _, o := range occurrences {
+		if o == occurrence {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,348 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testCalculatorShift(id, shiftType, start string, duration, level int, users ...string) *OnCallShift {
+	return &OnCallShift{
+		ID:         id,
+		ScheduleId: "SBM7DV7BKFUYU",
+		Type:       shiftType,
+		Name:       id,
+		Level:      level,
+		Start:      start,
+		Duration:   duration,
+		Users:      &users,
+	}
+}
+
+func testWeekly(shift *OnCallShift, byDay ...string) *OnCallShift {
+	frequency := FrequencyWeekly
+	shift.Frequency = &frequency
+	if len(byDay) > 0 {
+		shift.ByDay = &byDay
+	}
+	return shift
+}
+
+func testCalculatorShifts() []*OnCallShift {
+	rolling := testWeekly(testCalculatorShift("primary", OnCallShiftTypeRollingUsers, "2020-09-07T09:00:00", 12*3600, 0), "MO", "TU", "WE", "TH", "FR")
+	rolling.Users = nil
+	rolling.RollingUsers = &[][]string{{"A"}, {"B"}}
+
+	daily := FrequencyDaily
+	empty := testCalculatorShift("empty", OnCallShiftTypeRecurrentEvent, "2020-09-07T00:00:00", 24*3600, 5)
+	empty.Frequency = &daily
+
+	other := testCalculatorShift("other", OnCallShiftTypeSingleEvent, "2020-09-07T00:00:00", 30*24*3600, 9, "X")
+	other.ScheduleId = "OTHER"
+
+	return []*OnCallShift{
+		rolling,
+		testWeekly(testCalculatorShift("weekend", OnCallShiftTypeRecurrentEvent, "2020-09-12T00:00:00", 48*3600, 0, "C")),
+		testWeekly(testCalculatorShift("senior", OnCallShiftTypeRecurrentEvent, "2020-09-09T12:00:00", 2*3600, 1, "D")),
+		testCalculatorShift("pair", OnCallShiftTypeSingleEvent, "2020-09-08T10:00:00", 3600, 0, "E"),
+		testCalculatorShift("override", OnCallShiftTypeOverride, "2020-09-14T09:00:00", 4*3600, 0, "F"),
+		empty,
+		other,
+	}
+}
+
+func TestWhoIsOnCall(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		at    string
+		users []string
+	}{
+		{"before any shift", "2020-09-01T10:00:00Z", nil},
+		{"before first occurrence", "2020-09-07T08:59:59Z", nil},
+		{"first rotation", "2020-09-07T09:00:00Z", []string{"A"}},
+		{"end is exclusive", "2020-09-07T21:00:00Z", nil},
+		{"same level users join", "2020-09-08T10:30:00Z", []string{"A", "E"}},
+		{"after single event", "2020-09-08T11:00:00Z", []string{"A"}},
+		{"higher level wins", "2020-09-09T13:00:00Z", []string{"D"}},
+		{"back to lower level", "2020-09-09T14:00:00Z", []string{"A"}},
+		{"whole week of rotation", "2020-09-11T20:00:00Z", []string{"A"}},
+		{"weekend", "2020-09-12T10:00:00Z", []string{"C"}},
+		{"weekend second day", "2020-09-13T23:59:59Z", []string{"C"}},
+		{"override wins", "2020-09-14T10:00:00Z", []string{"F"}},
+		{"second rotation after override", "2020-09-14T13:00:00Z", []string{"B"}},
+		{"higher level recurs", "2020-09-16T13:00:00Z", []string{"D"}},
+		{"rotation wraps", "2020-09-21T10:00:00Z", []string{"A"}},
+		{"rotation wraps twice", "2020-09-28T10:00:00Z", []string{"B"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, err := calculator.WhoIsOnCall(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.users, users) {
+				t.Errorf("on call are %v, want %v", users, tt.users)
+			}
+		})
+	}
+}
+
+func TestOnCallIntervals(t *testing.T) {
+	daily := FrequencyDaily
+	sameUser := testCalculatorShift("daily", OnCallShiftTypeRecurrentEvent, "2020-09-07T00:00:00", 24*3600, 0, "A")
+	sameUser.Frequency = &daily
+
+	type interval struct {
+		start, end  string
+		users       []string
+		occurrences int
+	}
+	tests := []struct {
+		name      string
+		shifts    []*OnCallShift
+		start     string
+		end       string
+		intervals []interval
+	}{
+		{
+			name:   "split by same level shift",
+			shifts: testCalculatorShifts(),
+			start:  "2020-09-07T00:00:00Z",
+			end:    "2020-09-09T00:00:00Z",
+			intervals: []interval{
+				{"2020-09-07T09:00:00Z", "2020-09-07T21:00:00Z", []string{"A"}, 1},
+				{"2020-09-08T09:00:00Z", "2020-09-08T10:00:00Z", []string{"A"}, 1},
+				{"2020-09-08T10:00:00Z", "2020-09-08T11:00:00Z", []string{"A", "E"}, 2},
+				{"2020-09-08T11:00:00Z", "2020-09-08T21:00:00Z", []string{"A"}, 1},
+			},
+		},
+		{
+			name:   "clipped to window",
+			shifts: testCalculatorShifts(),
+			start:  "2020-09-09T13:00:00Z",
+			end:    "2020-09-09T15:00:00Z",
+			intervals: []interval{
+				{"2020-09-09T13:00:00Z", "2020-09-09T14:00:00Z", []string{"D"}, 1},
+				{"2020-09-09T14:00:00Z", "2020-09-09T15:00:00Z", []string{"A"}, 1},
+			},
+		},
+		{
+			name:   "weekend into rotation",
+			shifts: testCalculatorShifts(),
+			start:  "2020-09-12T00:00:00Z",
+			end:    "2020-09-15T00:00:00Z",
+			intervals: []interval{
+				{"2020-09-12T00:00:00Z", "2020-09-14T00:00:00Z", []string{"C"}, 1},
+				{"2020-09-14T09:00:00Z", "2020-09-14T13:00:00Z", []string{"F"}, 1},
+				{"2020-09-14T13:00:00Z", "2020-09-14T21:00:00Z", []string{"B"}, 1},
+			},
+		},
+		{
+			name:   "adjacent occurrences merged",
+			shifts: []*OnCallShift{sameUser},
+			start:  "2020-09-07T12:00:00Z",
+			end:    "2020-09-10T12:00:00Z",
+			intervals: []interval{
+				{"2020-09-07T12:00:00Z", "2020-09-10T12:00:00Z", []string{"A"}, 4},
+			},
+		},
+		{
+			name:   "nobody on call",
+			shifts: testCalculatorShifts(),
+			start:  "2020-09-07T21:00:00Z",
+			end:    "2020-09-08T09:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, tt.shifts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, _ := time.Parse(time.RFC3339, tt.start)
+			end, _ := time.Parse(time.RFC3339, tt.end)
+			intervals, err := calculator.OnCallIntervals(Window{Start: start, End: end})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []interval
+			for _, i := range intervals {
+				got = append(got, interval{
+					start:       i.Start.Format(time.RFC3339),
+					end:         i.End.Format(time.RFC3339),
+					users:       i.Users,
+					occurrences: len(i.Occurrences),
+				})
+			}
+			if !reflect.DeepEqual(tt.intervals, got) {
+				t.Errorf("intervals are\n %+v\nwant\n %+v", got, tt.intervals)
+			}
+		})
+	}
+}
+
+func TestOnCallCalculatorTimeZone(t *testing.T) {
+	daily := FrequencyDaily
+	shift := testCalculatorShift("daily", OnCallShiftTypeRecurrentEvent, "2020-10-20T09:00:00", 3600, 0, "A")
+	shift.Frequency = &daily
+
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU", TimeZone: "Europe/Berlin"}, []*OnCallShift{shift})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := calculator.Location().String(); got != "Europe/Berlin" {
+		t.Errorf("location is %s, want Europe/Berlin", got)
+	}
+
+	tests := []struct {
+		at    time.Time
+		users []string
+	}{
+		{time.Date(2020, 10, 23, 7, 30, 0, 0, time.UTC), []string{"A"}},
+		{time.Date(2020, 10, 23, 8, 30, 0, 0, time.UTC), nil},
+		{time.Date(2020, 10, 26, 7, 30, 0, 0, time.UTC), nil},
+		{time.Date(2020, 10, 26, 8, 30, 0, 0, time.UTC), []string{"A"}},
+	}
+	for _, tt := range tests {
+		users, err := calculator.WhoIsOnCall(tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.users, users) {
+			t.Errorf("on call at %s are %v, want %v", tt.at, users, tt.users)
+		}
+	}
+}
+
+func TestOnCallCalculatorOccurrences(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2020, 9, 13, 23, 0, 0, 0, time.UTC)
+	occurrences, err := calculator.Occurrences(Window{Start: start, End: start.Add(11 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, o := range occurrences {
+		got = append(got, fmt.Sprintf("%s %s %v %d %t", o.Shift.ID, o.Start.Format(time.RFC3339), o.Users, o.Level, o.Override))
+	}
+	want := []string{
+		"weekend 2020-09-12T00:00:00Z [C] 0 false",
+		"empty 2020-09-13T00:00:00Z [] 5 false",
+		"empty 2020-09-14T00:00:00Z [] 5 false",
+		"primary 2020-09-14T09:00:00Z [B] 0 false",
+		"override 2020-09-14T09:00:00Z [F] 0 true",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("occurrences are\n %v\nwant\n %v", got, want)
+	}
+
+	if _, err := calculator.Occurrences(Window{Start: start, End: start}); err == nil {
+		t.Error("expected error for empty window")
+	}
+}
+
+func TestNewOnCallCalculatorErrors(t *testing.T) {
+	hourly := "hourly"
+	zero := 0
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		shift    *OnCallShift
+	}{
+		{name: "no schedule"},
+		{name: "invalid time zone", schedule: &Schedule{TimeZone: "Mars/Olympus"}},
+		{name: "invalid start", shift: testCalculatorShift("S", OnCallShiftTypeSingleEvent, "2020-09-07 09:00", 3600, 0, "A")},
+		{name: "zero duration", shift: testCalculatorShift("S", OnCallShiftTypeSingleEvent, "2020-09-07T09:00:00", 0, 0, "A")},
+		{name: "unknown type", shift: testCalculatorShift("S", "shift", "2020-09-07T09:00:00", 3600, 0, "A")},
+		{name: "unsupported frequency", shift: func() *OnCallShift {
+			shift := testCalculatorShift("S", OnCallShiftTypeRecurrentEvent, "2020-09-07T09:00:00", 3600, 0, "A")
+			shift.Frequency = &hourly
+			return shift
+		}()},
+		{name: "zero interval", shift: func() *OnCallShift {
+			shift := testWeekly(testCalculatorShift("S", OnCallShiftTypeRecurrentEvent, "2020-09-07T09:00:00", 3600, 0, "A"))
+			shift.Interval = &zero
+			return shift
+		}()},
+		{name: "invalid by day", shift: testWeekly(testCalculatorShift("S", OnCallShiftTypeRecurrentEvent, "2020-09-07T09:00:00", 3600, 0, "A"), "MONDAY")},
+		{name: "invalid week start", shift: func() *OnCallShift {
+			shift := testWeekly(testCalculatorShift("S", OnCallShiftTypeRecurrentEvent, "2020-09-07T09:00:00", 3600, 0, "A"))
+			weekStart := "XX"
+			shift.WeekStart = &weekStart
+			return shift
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule
+			if schedule == nil && tt.name != "no schedule" {
+				schedule = &Schedule{ID: "SBM7DV7BKFUYU"}
+			}
+			var shifts []*OnCallShift
+			if tt.shift != nil {
+				shifts = append(shifts, tt.shift)
+			}
+			if _, err := NewOnCallCalculator(schedule, shifts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGetOnCallCalculator(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Test", "time_zone": "UTC", "on_call_now": []}`)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.URL.Query().Get("schedule_id"); got != "SBM7DV7BKFUYU" {
+			t.Errorf("schedule_id is %q, want %q", got, "SBM7DV7BKFUYU")
+		}
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [{
+			"id": "OH3V5FYQEYJ6M",
+			"schedule_id": "SBM7DV7BKFUYU",
+			"type": "recurrent_event",
+			"name": "Daily",
+			"level": 0,
+			"start": "2020-09-04T16:00:00",
+			"duration": 3600,
+			"frequency": "daily",
+			"interval": 1,
+			"users": ["U4DNY931HHJS5"]
+		}]}`)
+	})
+
+	calculator, err := client.Schedules.GetOnCallCalculator("SBM7DV7BKFUYU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := calculator.WhoIsOnCall(time.Date(2020, 9, 10, 16, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"U4DNY931HHJS5"}; !reflect.DeepEqual(want, users) {
+		t.Errorf("on call are %v, want %v", users, want)
+	}
+}
@@ -2,10 +2,12 @@ package amixr
 
 import (
 	"fmt"
-	"log"
 	"net/http"
+	"time"
 )
 
+// Handles requests to on-call shift endpoint
+// Use NewOnCallShiftService instead of direct creation OnCallShiftService
 type OnCallShiftService struct {
 	client *Client
 	url    string
@@ -19,6 +21,17 @@ func NewOnCallShiftService(client *Client) *OnCallShiftService {
 	return &onCallShiftService
 }
 
+// On-call shift types
+const (
+	OnCallShiftTypeSingleEvent    = "single_event"
+	OnCallShiftTypeRecurrentEvent = "recurrent_event"
+	OnCallShiftTypeRollingUsers   = "rolling_users"
+	OnCallShiftTypeOverride       = "override"
+)
+
+// onCallShiftTimeLayout is the format of OnCallShift.Start, which is a wall time in the schedule time zone
+const onCallShiftTimeLayout = "2006-01-02T15:04:05"
+
 type PaginatedOnCallShiftsResponse struct {
 	PaginatedResponse
 	OnCallShifts []*OnCallShift `json:"results"`
@@ -42,6 +55,15 @@ type OnCallShift struct {
 	RollingUsers *[][]string `json:"rolling_users"`
 }
 
+// StartTime parses shift start as wall time in given location, normally the schedule time zone
+func (shift *OnCallShift) StartTime(loc *time.Location) (time.Time, error) {
+	start, err := time.ParseInLocation(onCallShiftTimeLayout, shift.Start, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start %q of on-call shift %s: %w", shift.Start, shift.ID, err)
+	}
+	return start, nil
+}
+
 type ListOnCallShiftOptions struct {
 	ListOptions
 	ScheduleId string `url:"schedule_id,omitempty" json:"schedule_id,omitempty"`
@@ -49,10 +71,10 @@ type ListOnCallShiftOptions struct {
 }
 
 // ListOnCallShifts gets all on call shifts for authorized team
-func (service *OnCallShiftService) ListOnCallShifts(opt *ListOnCallShiftOptions) (*PaginatedOnCallShiftsResponse, *http.Response, error) {
+func (service *OnCallShiftService) ListOnCallShifts(opt *ListOnCallShiftOptions, options ...RequestOption) (*PaginatedOnCallShiftsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s/", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -66,14 +88,55 @@ func (service *OnCallShiftService) ListOnCallShifts(opt *ListOnCallShiftOptions)
 	return onCallShifts, resp, err
 }
 
+// OnCallShiftIterator iterates over on-call shifts of all pages.
+// Use OnCallShiftService.Iter to create one.
+type OnCallShiftIterator struct {
+	pager
+	items []*OnCallShift
+}
+
+// Iter returns iterator over all on-call shifts matching opt, following pagination links
+func (service *OnCallShiftService) Iter(opt *ListOnCallShiftOptions, options ...RequestOption) *OnCallShiftIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &OnCallShiftIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedOnCallShiftsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.OnCallShifts
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current onCallShift
+func (it *OnCallShiftIterator) Value() *OnCallShift {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllOnCallShifts gets on-call shifts of all pages
+func (service *OnCallShiftService) ListAllOnCallShifts(opt *ListOnCallShiftOptions, options ...RequestOption) ([]*OnCallShift, error) {
+	var onCallShifts []*OnCallShift
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		onCallShifts = append(onCallShifts, it.Value())
+	}
+	return onCallShifts, it.Err()
+}
+
 type GetOnCallShiftOptions struct {
 }
 
 // Get on-call shift by given id
-func (service *OnCallShiftService) GetOnCallShift(id string, opt *GetOnCallShiftOptions) (*OnCallShift, *http.Response, error) {
+func (service *OnCallShiftService) GetOnCallShift(id string, opt *GetOnCallShiftOptions, options ...RequestOption) (*OnCallShift, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -106,10 +169,10 @@ type CreateOnCallShiftOptions struct {
 }
 
 // Create on-call shift
-func (service *OnCallShiftService) CreateOnCallShift(opt *CreateOnCallShiftOptions) (*OnCallShift, *http.Response, error) {
-	log.Printf("[DEBUG] create amixr on_call_shift")
+func (service *OnCallShiftService) CreateOnCallShift(opt *CreateOnCallShiftOptions, options ...RequestOption) (*OnCallShift, *http.Response, error) {
+	service.client.logger.Debug("create amixr on_call_shift")
 	u := fmt.Sprintf("%s/", service.url)
-	req, err := service.client.NewRequest("POST", u, opt)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -143,10 +206,10 @@ type UpdateOnCallShiftOptions struct {
 }
 
 // Updates on-call shift
-func (service *OnCallShiftService) UpdateOnCallShift(id string, opt *UpdateOnCallShiftOptions) (*OnCallShift, *http.Response, error) {
+func (service *OnCallShiftService) UpdateOnCallShift(id string, opt *UpdateOnCallShiftOptions, options ...RequestOption) (*OnCallShift, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("PUT", u, opt)
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -164,11 +227,11 @@ type DeleteOnCallShiftOptions struct {
 }
 
 // Deletes on-call shift
-func (service *OnCallShiftService) DeleteOnCallShift(id string, opt *DeleteOnCallShiftOptions) (*http.Response, error) {
+func (service *OnCallShiftService) DeleteOnCallShift(id string, opt *DeleteOnCallShiftOptions, options ...RequestOption) (*http.Response, error) {
 
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("DELETE", u, opt)
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,83 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Handles requests to organization endpoint
+// Use NewOrganizationService instead of direct creation OrganizationService
+type OrganizationService struct {
+	client *Client
+	url    string
+}
+
+// NewOrganizationService creates OrganizationService with defined url
+func NewOrganizationService(client *Client) *OrganizationService {
+	organizationService := OrganizationService{}
+	organizationService.client = client
+	organizationService.url = "organization"
+	return &organizationService
+}
+
+type Organization struct {
+	ID                   string  `json:"id"`
+	Name                 string  `json:"name"`
+	MaintenanceMode      *string `json:"maintenance_mode"`
+	MaintenanceStartedAt *string `json:"maintenance_started_at"`
+	MaintenanceEndAt     *string `json:"maintenance_end_at"`
+}
+
+// MaintenanceDuration returns duration of current maintenance or debug mode.
+// ok is false if organization is not in maintenance.
+func (organization *Organization) MaintenanceDuration() (duration time.Duration, ok bool, err error) {
+	return maintenanceDuration(organization.MaintenanceStartedAt, organization.MaintenanceEndAt)
+}
+
+// GetOrganization gets organization of authorized team
+func (service *OrganizationService) GetOrganization(options ...RequestOption) (*Organization, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	organization := new(Organization)
+	resp, err := service.client.Do(req, organization)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return organization, resp, err
+}
+
+// StartMaintenance puts all integrations of organization into maintenance or debug mode
+func (service *OrganizationService) StartMaintenance(opt *StartMaintenanceOptions, options ...RequestOption) (*http.Response, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/maintenance_start/", service.url)
+
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
+
+// StopMaintenance returns organization from maintenance or debug mode
+func (service *OrganizationService) StopMaintenance(options ...RequestOption) (*http.Response, error) {
+	u := fmt.Sprintf("%s/maintenance_stop/", service.url)
+
+	req, err := service.client.NewRequest("POST", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
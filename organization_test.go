@@ -0,0 +1,77 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testOrganizationMaintenanceMode = MaintenanceModeMaintenance
+var testOrganizationMaintenanceStartedAt = "2020-09-04T13:00:00Z"
+var testOrganizationMaintenanceEndAt = "2020-09-04T14:00:00Z"
+
+var testOrganization = &Organization{
+	ID:                   "O9WTH7CKM3KZW",
+	Name:                 "Test Organization",
+	MaintenanceMode:      &testOrganizationMaintenanceMode,
+	MaintenanceStartedAt: &testOrganizationMaintenanceStartedAt,
+	MaintenanceEndAt:     &testOrganizationMaintenanceEndAt,
+}
+
+var testOrganizationBody = `{
+	"id": "O9WTH7CKM3KZW",
+	"name": "Test Organization",
+	"maintenance_mode": "maintenance",
+	"maintenance_started_at": "2020-09-04T13:00:00Z",
+	"maintenance_end_at": "2020-09-04T14:00:00Z"
+}`
+
+func TestGetOrganization(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/organization/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testOrganizationBody)
+	})
+
+	organization, _, err := client.Organization.GetOrganization()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testOrganization
+
+	if !reflect.DeepEqual(want, organization) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", organization, want)
+	}
+}
+
+func TestOrganizationMaintenance(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	var calls []string
+	mux.HandleFunc("/api/v1/organization/maintenance_start/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		calls = append(calls, "start")
+	})
+	mux.HandleFunc("/api/v1/organization/maintenance_stop/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		calls = append(calls, "stop")
+	})
+
+	options := &StartMaintenanceOptions{Mode: MaintenanceModeMaintenance, Duration: 3600}
+	if _, err := client.Organization.StartMaintenance(options); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Organization.StopMaintenance(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"start", "stop"}
+	if !reflect.DeepEqual(want, calls) {
+		t.Errorf("called %v, want %v", calls, want)
+	}
+}
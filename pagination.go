@@ -0,0 +1,126 @@
+package amixr
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// paginated is implemented by every Paginated...Response through embedded PaginatedResponse
+type paginated interface {
+	pagination() *PaginatedResponse
+}
+
+func (p *PaginatedResponse) pagination() *PaginatedResponse {
+	return p
+}
+
+// pageIterator fetches consecutive pages of a list endpoint following Next links.
+// Query of the Next link is applied to the list path of the client base URL,
+// so the token is never sent to a host returned by API.
+type pageIterator struct {
+	client  *Client
+	path    string
+	opt     interface{}
+	options []RequestOption
+
+	nextQuery string
+	started   bool
+	done      bool
+	err       error
+}
+
+func newPageIterator(client *Client, path string, opt interface{}, options []RequestOption) *pageIterator {
+	return &pageIterator{
+		client:  client,
+		path:    path,
+		opt:     opt,
+		options: options,
+	}
+}
+
+// next decodes the next page into page. Returns false when there are no more pages or request failed.
+func (it *pageIterator) next(page paginated) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	var opt interface{}
+	if !it.started {
+		opt = it.opt
+	}
+	req, err := it.client.NewRequest("GET", it.path, opt, it.options...)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if it.started {
+		req.URL.RawQuery = it.nextQuery
+	}
+	if err := req.Context().Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if _, err := it.client.Do(req, page); err != nil {
+		it.err = err
+		return false
+	}
+	it.started = true
+
+	next := page.pagination().Next
+	if next == nil || *next == "" {
+		it.done = true
+		return true
+	}
+	nextURL, err := url.Parse(*next)
+	if err != nil {
+		it.err = fmt.Errorf("invalid next page link %q: %w", *next, err)
+		return false
+	}
+	if nextURL.RawQuery == "" || nextURL.RawQuery == it.nextQuery {
+		it.err = fmt.Errorf("next page link %q does not advance pagination", *next)
+		return false
+	}
+	it.nextQuery = nextURL.RawQuery
+	return true
+}
+
+// pager iterates over items of all pages, typed iterators embed it and keep items of the current page.
+// fetch decodes the next page with pages and returns number of its items, false when there are no more pages.
+type pager struct {
+	pages  *pageIterator
+	fetch  func(pages *pageIterator) (int, bool)
+	length int
+	index  int
+}
+
+func newPager(client *Client, path string, opt interface{}, options []RequestOption, fetch func(pages *pageIterator) (int, bool)) pager {
+	return pager{pages: newPageIterator(client, path, opt, options), fetch: fetch, index: -1}
+}
+
+// Next advances iterator. It returns false when there are no more items or request failed, see Err
+func (p *pager) Next() bool {
+	for p.index+1 >= p.length {
+		length, ok := p.fetch(p.pages)
+		if !ok {
+			p.length, p.index = 0, -1
+			return false
+		}
+		p.length, p.index = length, -1
+	}
+	p.index++
+	return true
+}
+
+// Err returns error which stopped iteration, if any
+func (p *pager) Err() error {
+	return p.pages.err
+}
+
+// current returns index of the current item within the page, -1 before Next and after iteration
+func (p *pager) current() int {
+	if p.index >= p.length {
+		return -1
+	}
+	return p.index
+}
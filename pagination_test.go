@@ -0,0 +1,135 @@
+package amixr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestIntegrationIterator(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integrations/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		switch r.URL.Query().Get("page") {
+		case "":
+			// next link may point to a public host behind a proxy, only its query should be used
+			fmt.Fprintf(w, `{"count": 3, "next": "https://public.example.com/api/v1/integrations/?page=2", "previous": null, "results": [%s]}`, testIntegrationBody)
+		case "2":
+			fmt.Fprint(w, `{"count": 3, "next": "https://public.example.com/api/v1/integrations/?page=3", "previous": null, "results": []}`)
+		case "3":
+			fmt.Fprintf(w, `{"count": 3, "next": null, "previous": null, "results": [%s, %s]}`, testIntegrationBody, testIntegrationBody)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	it := client.Integrations.Iter(&ListIntegrationOptions{})
+	var integrations []*Integration
+	for it.Next() {
+		integrations = append(integrations, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Integration{testIntegration, testIntegration, testIntegration}
+	if !reflect.DeepEqual(want, integrations) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", integrations, want)
+	}
+	if it.Next() || it.Value() != nil {
+		t.Error("exhausted iterator should stay exhausted")
+	}
+}
+
+func TestListAllRoutesKeepsFilters(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/routes", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("integration_id"); got != "CGB8GB9Z4NUXF" {
+			t.Errorf("integration_id is %q, want %q", got, "CGB8GB9Z4NUXF")
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"count": 2, "next": "%s/api/v1/routes?integration_id=CGB8GB9Z4NUXF&page=2", "previous": null, "results": [%s]}`, server.URL, testRouteBody)
+		case "2":
+			fmt.Fprintf(w, `{"count": 2, "next": null, "previous": null, "results": [%s]}`, testRouteBody)
+		}
+	})
+
+	routes, err := client.Routes.ListAllRoutes(&ListRouteOptions{IntegrationId: "CGB8GB9Z4NUXF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 {
+		t.Errorf("returned %d routes, want 2", len(routes))
+	}
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"detail": "Invalid page."}`)
+			return
+		}
+		fmt.Fprintf(w, `{"count": 2, "next": "%s/api/v1/users/?page=2", "previous": null, "results": [%s]}`, server.URL, testUserBody)
+	})
+
+	users, err := client.Users.ListAllUsers(&ListUserOptions{})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("returned %d users, want users of the first page", len(users))
+	}
+}
+
+func TestIteratorStopsOnContextCancel(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc("/api/v1/schedules/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			t.Error("request should not be sent after cancellation")
+		}
+		fmt.Fprintf(w, `{"count": 2, "next": "%s/api/v1/schedules/?page=2", "previous": null, "results": [%s]}`, server.URL, testScheduleBody)
+	})
+
+	it := client.Schedules.Iter(&ListScheduleOptions{}, WithContext(ctx))
+	if !it.Next() {
+		t.Fatalf("expected first schedule, got error %v", it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Fatal("expected iteration to stop")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", it.Err())
+	}
+}
+
+func TestIteratorDetectsStuckPagination(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"count": 2, "next": "%s/api/v1/on_call_shifts/?page=2", "previous": null, "results": [%s]}`, server.URL, testOnCallShiftBody)
+	})
+
+	_, err := client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{})
+	if err == nil {
+		t.Fatal("expected error for next link pointing to the same page")
+	}
+}
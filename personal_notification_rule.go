@@ -0,0 +1,205 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Handles requests to personal notification rule endpoint
+// Personal notification rules define how and when a user is notified, step by step.
+// Use NewPersonalNotificationRuleService instead of direct creation PersonalNotificationRuleService
+type PersonalNotificationRuleService struct {
+	client *Client
+	url    string
+}
+
+// NewPersonalNotificationRuleService creates PersonalNotificationRuleService with defined url
+func NewPersonalNotificationRuleService(client *Client) *PersonalNotificationRuleService {
+	personalNotificationRuleService := PersonalNotificationRuleService{}
+	personalNotificationRuleService.client = client
+	personalNotificationRuleService.url = "personal_notification_rules"
+	return &personalNotificationRuleService
+}
+
+// Personal notification rule types
+const (
+	NotificationRuleTypeWait              = "wait"
+	NotificationRuleTypeNotifyBySlack     = "notify_by_slack"
+	NotificationRuleTypeNotifyBySMS       = "notify_by_sms"
+	NotificationRuleTypeNotifyByPhoneCall = "notify_by_phone_call"
+	NotificationRuleTypeNotifyByTelegram  = "notify_by_telegram"
+	NotificationRuleTypeNotifyByEmail     = "notify_by_email"
+)
+
+type PaginatedPersonalNotificationRulesResponse struct {
+	PaginatedResponse
+	PersonalNotificationRules []*PersonalNotificationRule `json:"results"`
+}
+
+type PersonalNotificationRule struct {
+	ID       string `json:"id"`
+	UserId   string `json:"user_id"`
+	Position int    `json:"position"`
+	// Important rules are used for escalation steps marked as important, default ones otherwise
+	Important bool   `json:"important"`
+	Type      string `json:"type"`
+	// Duration is wait time in seconds for rules of wait type
+	Duration *int `json:"duration"`
+}
+
+type ListPersonalNotificationRuleOptions struct {
+	ListOptions
+	UserId    string `url:"user_id,omitempty" json:"user_id,omitempty"`
+	Important *bool  `url:"important,omitempty" json:"important,omitempty"`
+}
+
+// ListPersonalNotificationRules gets personal notification rules for authorized team
+func (service *PersonalNotificationRuleService) ListPersonalNotificationRules(opt *ListPersonalNotificationRuleOptions, options ...RequestOption) (*PaginatedPersonalNotificationRulesResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var personalNotificationRules *PaginatedPersonalNotificationRulesResponse
+	resp, err := service.client.Do(req, &personalNotificationRules)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return personalNotificationRules, resp, err
+}
+
+// PersonalNotificationRuleIterator iterates over personal notification rules of all pages.
+// Use PersonalNotificationRuleService.Iter to create one.
+type PersonalNotificationRuleIterator struct {
+	pager
+	items []*PersonalNotificationRule
+}
+
+// Iter returns iterator over all personal notification rules matching opt, following pagination links
+func (service *PersonalNotificationRuleService) Iter(opt *ListPersonalNotificationRuleOptions, options ...RequestOption) *PersonalNotificationRuleIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &PersonalNotificationRuleIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedPersonalNotificationRulesResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.PersonalNotificationRules
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current personalNotificationRule
+func (it *PersonalNotificationRuleIterator) Value() *PersonalNotificationRule {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllPersonalNotificationRules gets personal notification rules of all pages
+func (service *PersonalNotificationRuleService) ListAllPersonalNotificationRules(opt *ListPersonalNotificationRuleOptions, options ...RequestOption) ([]*PersonalNotificationRule, error) {
+	var personalNotificationRules []*PersonalNotificationRule
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		personalNotificationRules = append(personalNotificationRules, it.Value())
+	}
+	return personalNotificationRules, it.Err()
+}
+
+type GetPersonalNotificationRuleOptions struct {
+}
+
+// Get personal notification rule by given id
+func (service *PersonalNotificationRuleService) GetPersonalNotificationRule(id string, opt *GetPersonalNotificationRuleOptions, options ...RequestOption) (*PersonalNotificationRule, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	personalNotificationRule := new(PersonalNotificationRule)
+	resp, err := service.client.Do(req, personalNotificationRule)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return personalNotificationRule, resp, err
+}
+
+type CreatePersonalNotificationRuleOptions struct {
+	UserId      string `json:"user_id"`
+	Position    *int   `json:"position,omitempty"`
+	Important   bool   `json:"important"`
+	Type        string `json:"type"`
+	Duration    *int   `json:"duration,omitempty"`
+	ManualOrder bool   `json:"manual_order,omitempty"`
+}
+
+// Create personal notification rule
+func (service *PersonalNotificationRuleService) CreatePersonalNotificationRule(opt *CreatePersonalNotificationRuleOptions, options ...RequestOption) (*PersonalNotificationRule, *http.Response, error) {
+	service.client.logger.Debug("create amixr personal notification rule")
+	u := fmt.Sprintf("%s/", service.url)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	personalNotificationRule := new(PersonalNotificationRule)
+
+	resp, err := service.client.Do(req, personalNotificationRule)
+
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return personalNotificationRule, resp, err
+}
+
+type UpdatePersonalNotificationRuleOptions struct {
+	Position    *int   `json:"position,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Duration    *int   `json:"duration,omitempty"`
+	ManualOrder bool   `json:"manual_order,omitempty"`
+}
+
+// Updates personal notification rule
+func (service *PersonalNotificationRuleService) UpdatePersonalNotificationRule(id string, opt *UpdatePersonalNotificationRuleOptions, options ...RequestOption) (*PersonalNotificationRule, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	personalNotificationRule := new(PersonalNotificationRule)
+	resp, err := service.client.Do(req, personalNotificationRule)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return personalNotificationRule, resp, err
+}
+
+type DeletePersonalNotificationRuleOptions struct {
+}
+
+// Deletes personal notification rule
+func (service *PersonalNotificationRuleService) DeletePersonalNotificationRule(id string, opt *DeletePersonalNotificationRuleOptions, options ...RequestOption) (*http.Response, error) {
+
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.Do(req, nil)
+	return resp, err
+}
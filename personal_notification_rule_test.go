@@ -0,0 +1,155 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testPersonalNotificationRuleDuration = 300
+
+var testPersonalNotificationRule = &PersonalNotificationRule{
+	ID:        "NT79GA9I7E4DJ",
+	UserId:    "U4DNY931HHJS5",
+	Position:  1,
+	Important: false,
+	Type:      NotificationRuleTypeWait,
+	Duration:  &testPersonalNotificationRuleDuration,
+}
+
+var testPersonalNotificationRuleBody = `{
+	"id": "NT79GA9I7E4DJ",
+	"user_id": "U4DNY931HHJS5",
+	"position": 1,
+	"important": false,
+	"type": "wait",
+	"duration": 300
+}`
+
+func TestCreatePersonalNotificationRule(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/personal_notification_rules/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "POST")
+		fmt.Fprint(w, testPersonalNotificationRuleBody)
+	})
+
+	position := 1
+	createOptions := &CreatePersonalNotificationRuleOptions{
+		UserId:   "U4DNY931HHJS5",
+		Position: &position,
+		Type:     NotificationRuleTypeWait,
+		Duration: &testPersonalNotificationRuleDuration,
+	}
+	rule, _, err := client.PersonalNotificationRules.CreatePersonalNotificationRule(createOptions)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testPersonalNotificationRule
+
+	if !reflect.DeepEqual(want, rule) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", rule, want)
+	}
+}
+
+func TestListPersonalNotificationRules(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/personal_notification_rules/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		q := r.URL.Query()
+		if q.Get("user_id") != "U4DNY931HHJS5" || q.Get("important") != "false" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testPersonalNotificationRuleBody))
+	})
+
+	important := false
+	options := &ListPersonalNotificationRuleOptions{
+		UserId:    "U4DNY931HHJS5",
+		Important: &important,
+	}
+
+	rules, _, err := client.PersonalNotificationRules.ListPersonalNotificationRules(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PaginatedPersonalNotificationRulesResponse{
+		PaginatedResponse: PaginatedResponse{
+			Count:    1,
+			Next:     nil,
+			Previous: nil,
+		},
+		PersonalNotificationRules: []*PersonalNotificationRule{
+			testPersonalNotificationRule,
+		},
+	}
+	if !reflect.DeepEqual(want, rules) {
+		t.Errorf("returned\n %+v, \nwant\n %+v", rules, want)
+	}
+}
+
+func TestGetPersonalNotificationRule(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/personal_notification_rules/NT79GA9I7E4DJ/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testPersonalNotificationRuleBody)
+	})
+
+	rule, _, err := client.PersonalNotificationRules.GetPersonalNotificationRule("NT79GA9I7E4DJ", &GetPersonalNotificationRuleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testPersonalNotificationRule
+
+	if !reflect.DeepEqual(want, rule) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", rule, want)
+	}
+}
+
+func TestUpdatePersonalNotificationRule(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/personal_notification_rules/NT79GA9I7E4DJ/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "PUT")
+		fmt.Fprint(w, testPersonalNotificationRuleBody)
+	})
+
+	updateOptions := &UpdatePersonalNotificationRuleOptions{
+		Duration: &testPersonalNotificationRuleDuration,
+	}
+	rule, _, err := client.PersonalNotificationRules.UpdatePersonalNotificationRule("NT79GA9I7E4DJ", updateOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testPersonalNotificationRule
+
+	if !reflect.DeepEqual(want, rule) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", rule, want)
+	}
+}
+
+func TestDeletePersonalNotificationRule(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/personal_notification_rules/NT79GA9I7E4DJ/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "DELETE")
+	})
+
+	_, err := client.PersonalNotificationRules.DeletePersonalNotificationRule("NT79GA9I7E4DJ", &DeletePersonalNotificationRuleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
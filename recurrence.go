@@ -0,0 +1,290 @@
+package amixr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies of on-call shifts
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// maxRecurrenceDays bounds expansion of a single recurrence
+const maxRecurrenceDays = 366 * 200
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// weekdayNum is a BYDAY value, n is an optional ordinal within month (or year), e.g. -1 for the last one
+type weekdayNum struct {
+	weekday time.Weekday
+	n       int
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	weekday, ok := weekdays[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q", s)
+	}
+	return weekday, nil
+}
+
+func parseWeekdayNum(s string) (weekdayNum, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return weekdayNum{}, fmt.Errorf("unknown weekday %q", s)
+	}
+	weekday, err := parseWeekday(s[len(s)-2:])
+	if err != nil {
+		return weekdayNum{}, err
+	}
+	var n int
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n > 53 || n < -53 {
+			return weekdayNum{}, fmt.Errorf("invalid weekday ordinal %q", s)
+		}
+	}
+	return weekdayNum{weekday: weekday, n: n}, nil
+}
+
+// recurrence is the subset of RFC 5545 RRULE used by on-call shifts and iCal feeds.
+// Zero frequency means a single occurrence.
+type recurrence struct {
+	frequency  string
+	interval   int
+	weekStart  time.Weekday
+	byDay      []weekdayNum
+	byMonth    []int
+	byMonthday []int
+	// count limits number of occurrences, 0 is unlimited
+	count int
+	// until is the last possible occurrence start, zero is unlimited
+	until time.Time
+	// exclude are starts of removed occurrences, they still count towards count
+	exclude []time.Time
+}
+
+func (r *recurrence) validate() error {
+	switch r.frequency {
+	case "", FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return fmt.Errorf("unsupported frequency %q", r.frequency)
+	}
+	if r.interval < 1 {
+		return fmt.Errorf("interval must be positive, got %d", r.interval)
+	}
+	for _, month := range r.byMonth {
+		if month < 1 || month > 12 {
+			return fmt.Errorf("invalid month %d", month)
+		}
+	}
+	for _, monthday := range r.byMonthday {
+		if monthday == 0 || monthday > 31 || monthday < -31 {
+			return fmt.Errorf("invalid day of month %d", monthday)
+		}
+	}
+	if r.count < 0 {
+		return fmt.Errorf("count must not be negative, got %d", r.count)
+	}
+	return nil
+}
+
+// expand calls fn with start of every occurrence before given time, in order,
+// along with number of the recurrence period it belongs to, e.g. number of weeks
+// (by interval) since dtstart for weekly recurrence. Expansion stops if fn returns false.
+func (r *recurrence) expand(dtstart, before time.Time, fn func(start time.Time, period int) bool) error {
+	if r.frequency == "" {
+		if dtstart.Before(before) && !r.excluded(dtstart) {
+			fn(dtstart, 0)
+		}
+		return nil
+	}
+
+	loc := dtstart.Location()
+	hour, min, sec := dtstart.Clock()
+	first := civilDate(dtstart)
+	days := int(civilDate(before.In(loc)).Sub(first)/(24*time.Hour)) + 1
+	if days > maxRecurrenceDays {
+		return fmt.Errorf("recurrence from %s to %s is too long to expand", dtstart, before)
+	}
+
+	matched := 0
+	for i := 0; i < days; i++ {
+		day := first.Add(time.Duration(i) * 24 * time.Hour)
+		period, ok := r.match(first, day)
+		if !ok {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, dtstart.Nanosecond(), loc)
+		if start.Before(dtstart) {
+			continue
+		}
+		if !start.Before(before) || (!r.until.IsZero() && start.After(r.until)) {
+			return nil
+		}
+		matched++
+		if r.count > 0 && matched > r.count {
+			return nil
+		}
+		if r.excluded(start) {
+			continue
+		}
+		if !fn(start, period) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// match reports whether recurrence has an occurrence on civil day and returns its period
+func (r *recurrence) match(first, day time.Time) (int, bool) {
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(day.Month())) {
+		return 0, false
+	}
+
+	var units int
+	switch r.frequency {
+	case FrequencyDaily:
+		units = int(day.Sub(first) / (24 * time.Hour))
+		if len(r.byDay) > 0 && !r.matchWeekday(day, false) {
+			return 0, false
+		}
+		if len(r.byMonthday) > 0 && !matchMonthday(r.byMonthday, day) {
+			return 0, false
+		}
+
+	case FrequencyWeekly:
+		units = int(r.startOfWeek(day).Sub(r.startOfWeek(first)) / (7 * 24 * time.Hour))
+		if len(r.byDay) > 0 {
+			if !r.matchWeekday(day, false) {
+				return 0, false
+			}
+		} else if day.Weekday() != first.Weekday() {
+			return 0, false
+		}
+		if len(r.byMonthday) > 0 && !matchMonthday(r.byMonthday, day) {
+			return 0, false
+		}
+
+	case FrequencyMonthly:
+		units = (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		if !r.matchDay(first, day, false) {
+			return 0, false
+		}
+
+	case FrequencyYearly:
+		units = day.Year() - first.Year()
+		if len(r.byMonth) == 0 && len(r.byMonthday) == 0 && len(r.byDay) == 0 && day.Month() != first.Month() {
+			return 0, false
+		}
+		if !r.matchDay(first, day, len(r.byMonth) == 0) {
+			return 0, false
+		}
+	}
+
+	if units%r.interval != 0 {
+		return 0, false
+	}
+	return units / r.interval, true
+}
+
+// matchDay checks BYMONTHDAY and BYDAY of monthly and yearly recurrences,
+// falling back to day of month of dtstart
+func (r *recurrence) matchDay(first, day time.Time, ordinalInYear bool) bool {
+	if len(r.byMonthday) == 0 && len(r.byDay) == 0 {
+		return day.Day() == first.Day()
+	}
+	if len(r.byMonthday) > 0 && !matchMonthday(r.byMonthday, day) {
+		return false
+	}
+	if len(r.byDay) > 0 && !r.matchWeekday(day, ordinalInYear) {
+		return false
+	}
+	return true
+}
+
+func (r *recurrence) matchWeekday(day time.Time, ordinalInYear bool) bool {
+	for _, wd := range r.byDay {
+		if wd.weekday != day.Weekday() {
+			continue
+		}
+		if wd.n == 0 || (r.frequency != FrequencyMonthly && r.frequency != FrequencyYearly) {
+			return true
+		}
+		var position, total int
+		if ordinalInYear {
+			position = day.YearDay()
+			total = time.Date(day.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		} else {
+			position = day.Day()
+			total = daysInMonth(day)
+		}
+		if wd.n > 0 && (position-1)/7+1 == wd.n {
+			return true
+		}
+		if wd.n < 0 && (total-position)/7+1 == -wd.n {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrence) startOfWeek(day time.Time) time.Time {
+	shift := (int(day.Weekday()) - int(r.weekStart) + 7) % 7
+	return day.Add(-time.Duration(shift) * 24 * time.Hour)
+}
+
+func (r *recurrence) excluded(start time.Time) bool {
+	for _, t := range r.exclude {
+		if t.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMonthday(monthdays []int, day time.Time) bool {
+	total := daysInMonth(day)
+	for _, monthday := range monthdays {
+		if monthday > 0 && monthday == day.Day() {
+			return true
+		}
+		if monthday < 0 && total+monthday+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// civilDate returns date of t as midnight UTC, so days can be counted without DST shifts
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
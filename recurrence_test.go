@@ -0,0 +1,346 @@
+package amixr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecurrenceExpand(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	weekdays := func(days ...string) []weekdayNum {
+		var result []weekdayNum
+		for _, day := range days {
+			wd, err := parseWeekdayNum(day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = append(result, wd)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		recurrence recurrence
+		dtstart    time.Time
+		before     time.Time
+		starts     []string
+		periods    []int
+	}{
+		{
+			name:       "single",
+			recurrence: recurrence{interval: 1},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2021-01-01 00:00"),
+			starts:     []string{"2020-09-07 09:00"},
+			periods:    []int{0},
+		},
+		{
+			name:       "single after window",
+			recurrence: recurrence{interval: 1},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-07 09:00"),
+		},
+		{
+			name:       "daily",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-10 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-08 09:00", "2020-09-09 09:00"},
+			periods:    []int{0, 1, 2},
+		},
+		{
+			name:       "daily before is exclusive",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-09 09:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-08 09:00"},
+			periods:    []int{0, 1},
+		},
+		{
+			name:       "daily every other day",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 2},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-14 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-09 09:00", "2020-09-11 09:00", "2020-09-13 09:00"},
+			periods:    []int{0, 1, 2, 3},
+		},
+		{
+			name:       "daily by month",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1, byMonth: []int{12}},
+			dtstart:    date("2020-11-30 09:00"),
+			before:     date("2020-12-03 00:00"),
+			starts:     []string{"2020-12-01 09:00", "2020-12-02 09:00"},
+			periods:    []int{1, 2},
+		},
+		{
+			name:       "daily by day",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1, byDay: weekdays("SA", "SU")},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-14 00:00"),
+			starts:     []string{"2020-09-12 09:00", "2020-09-13 09:00"},
+			periods:    []int{5, 6},
+		},
+		{
+			name:       "weekly",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 1},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-22 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-14 09:00", "2020-09-21 09:00"},
+			periods:    []int{0, 1, 2},
+		},
+		{
+			name:       "weekly by day",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 1, byDay: weekdays("MO", "WE", "FR")},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-15 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-09 09:00", "2020-09-11 09:00", "2020-09-14 09:00"},
+			periods:    []int{0, 0, 0, 1},
+		},
+		{
+			name:       "weekly by day skips dtstart",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 2, byDay: weekdays("TU", "TH")},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2020-09-25 00:00"),
+			starts:     []string{"2020-09-08 09:00", "2020-09-10 09:00", "2020-09-22 09:00", "2020-09-24 09:00"},
+			periods:    []int{0, 0, 1, 1},
+		},
+		{
+			// RFC 5545 example of WKST effect
+			name:       "weekly week start monday",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 2, count: 4, weekStart: time.Monday, byDay: weekdays("TU", "SU")},
+			dtstart:    date("1997-08-05 09:00"),
+			before:     date("1998-01-01 00:00"),
+			starts:     []string{"1997-08-05 09:00", "1997-08-10 09:00", "1997-08-19 09:00", "1997-08-24 09:00"},
+			periods:    []int{0, 0, 1, 1},
+		},
+		{
+			name:       "weekly week start sunday",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 2, count: 4, weekStart: time.Sunday, byDay: weekdays("TU", "SU")},
+			dtstart:    date("1997-08-05 09:00"),
+			before:     date("1998-01-01 00:00"),
+			starts:     []string{"1997-08-05 09:00", "1997-08-17 09:00", "1997-08-19 09:00", "1997-08-31 09:00"},
+			periods:    []int{0, 1, 1, 2},
+		},
+		{
+			name:       "monthly skips short months",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 1},
+			dtstart:    date("2020-01-31 09:00"),
+			before:     date("2020-06-01 00:00"),
+			starts:     []string{"2020-01-31 09:00", "2020-03-31 09:00", "2020-05-31 09:00"},
+			periods:    []int{0, 2, 4},
+		},
+		{
+			name:       "monthly last day",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 1, byMonthday: []int{-1}},
+			dtstart:    date("2020-01-31 09:00"),
+			before:     date("2020-05-01 00:00"),
+			starts:     []string{"2020-01-31 09:00", "2020-02-29 09:00", "2020-03-31 09:00", "2020-04-30 09:00"},
+			periods:    []int{0, 1, 2, 3},
+		},
+		{
+			name:       "monthly by month days",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 2, byMonthday: []int{1, 15}},
+			dtstart:    date("2020-01-01 09:00"),
+			before:     date("2020-04-01 00:00"),
+			starts:     []string{"2020-01-01 09:00", "2020-01-15 09:00", "2020-03-01 09:00", "2020-03-15 09:00"},
+			periods:    []int{0, 0, 1, 1},
+		},
+		{
+			name:       "monthly last friday",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 1, byDay: weekdays("-1FR")},
+			dtstart:    date("2020-09-01 09:00"),
+			before:     date("2020-12-01 00:00"),
+			starts:     []string{"2020-09-25 09:00", "2020-10-30 09:00", "2020-11-27 09:00"},
+			periods:    []int{0, 1, 2},
+		},
+		{
+			name:       "monthly second tuesday",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 1, byDay: weekdays("+2TU")},
+			dtstart:    date("2020-09-01 09:00"),
+			before:     date("2020-11-01 00:00"),
+			starts:     []string{"2020-09-08 09:00", "2020-10-13 09:00"},
+			periods:    []int{0, 1},
+		},
+		{
+			name:       "monthly friday 13th",
+			recurrence: recurrence{frequency: FrequencyMonthly, interval: 1, byDay: weekdays("FR"), byMonthday: []int{13}},
+			dtstart:    date("2020-01-01 09:00"),
+			before:     date("2021-01-01 00:00"),
+			starts:     []string{"2020-03-13 09:00", "2020-11-13 09:00"},
+			periods:    []int{2, 10},
+		},
+		{
+			name:       "yearly leap day",
+			recurrence: recurrence{frequency: FrequencyYearly, interval: 1},
+			dtstart:    date("2020-02-29 09:00"),
+			before:     date("2025-01-01 00:00"),
+			starts:     []string{"2020-02-29 09:00", "2024-02-29 09:00"},
+			periods:    []int{0, 4},
+		},
+		{
+			name:       "yearly by month and month day",
+			recurrence: recurrence{frequency: FrequencyYearly, interval: 1, byMonth: []int{12}, byMonthday: []int{25}},
+			dtstart:    date("2020-01-01 00:00"),
+			before:     date("2022-01-01 00:00"),
+			starts:     []string{"2020-12-25 00:00", "2021-12-25 00:00"},
+			periods:    []int{0, 1},
+		},
+		{
+			name:       "yearly first monday of month",
+			recurrence: recurrence{frequency: FrequencyYearly, interval: 1, byMonth: []int{9}, byDay: weekdays("1MO")},
+			dtstart:    date("2020-01-01 00:00"),
+			before:     date("2022-01-01 00:00"),
+			starts:     []string{"2020-09-07 00:00", "2021-09-06 00:00"},
+			periods:    []int{0, 1},
+		},
+		{
+			name:       "count",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1, count: 3},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2021-01-01 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-08 09:00", "2020-09-09 09:00"},
+			periods:    []int{0, 1, 2},
+		},
+		{
+			name:       "until is inclusive",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1, until: date("2020-09-09 09:00")},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2021-01-01 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-08 09:00", "2020-09-09 09:00"},
+			periods:    []int{0, 1, 2},
+		},
+		{
+			name:       "excluded occurrences count",
+			recurrence: recurrence{frequency: FrequencyDaily, interval: 1, count: 3, exclude: []time.Time{date("2020-09-08 09:00")}},
+			dtstart:    date("2020-09-07 09:00"),
+			before:     date("2021-01-01 00:00"),
+			starts:     []string{"2020-09-07 09:00", "2020-09-09 09:00"},
+			periods:    []int{0, 2},
+		},
+		{
+			name:       "wall time kept over DST change",
+			recurrence: recurrence{frequency: FrequencyWeekly, interval: 1},
+			dtstart:    time.Date(2020, 10, 19, 9, 0, 0, 0, berlin),
+			before:     time.Date(2020, 10, 27, 0, 0, 0, 0, berlin),
+			starts:     []string{"2020-10-19 09:00 CEST", "2020-10-26 09:00 CET"},
+			periods:    []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.recurrence.validate(); err != nil {
+				t.Fatal(err)
+			}
+			var starts []string
+			var periods []int
+			err := tt.recurrence.expand(tt.dtstart, tt.before, func(start time.Time, period int) bool {
+				layout := "2006-01-02 15:04"
+				if start.Location() != time.UTC {
+					layout += " MST"
+				}
+				starts = append(starts, start.Format(layout))
+				periods = append(periods, period)
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.starts, starts) {
+				t.Errorf("starts are %v, want %v", starts, tt.starts)
+			}
+			if !reflect.DeepEqual(tt.periods, periods) {
+				t.Errorf("periods are %v, want %v", periods, tt.periods)
+			}
+		})
+	}
+}
+
+func TestRecurrenceExpandStops(t *testing.T) {
+	r := recurrence{frequency: FrequencyDaily, interval: 1}
+	dtstart := time.Date(2020, 9, 7, 9, 0, 0, 0, time.UTC)
+
+	calls := 0
+	err := r.expand(dtstart, dtstart.AddDate(1, 0, 0), func(time.Time, int) bool {
+		calls++
+		return calls < 2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("called %d times, want 2", calls)
+	}
+
+	if err := r.expand(dtstart, dtstart.AddDate(300, 0, 0), func(time.Time, int) bool { return true }); err == nil {
+		t.Error("expected error for too long expansion")
+	}
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence recurrence
+	}{
+		{"unknown frequency", recurrence{frequency: "hourly", interval: 1}},
+		{"zero interval", recurrence{frequency: FrequencyDaily}},
+		{"invalid month", recurrence{frequency: FrequencyDaily, interval: 1, byMonth: []int{13}}},
+		{"zero month day", recurrence{frequency: FrequencyDaily, interval: 1, byMonthday: []int{0}}},
+		{"invalid month day", recurrence{frequency: FrequencyDaily, interval: 1, byMonthday: []int{-32}}},
+		{"negative count", recurrence{frequency: FrequencyDaily, interval: 1, count: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.recurrence.validate(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseWeekdayNum(t *testing.T) {
+	tests := []struct {
+		value string
+		want  weekdayNum
+		err   bool
+	}{
+		{value: "MO", want: weekdayNum{weekday: time.Monday}},
+		{value: "su", want: weekdayNum{weekday: time.Sunday}},
+		{value: "+2TU", want: weekdayNum{weekday: time.Tuesday, n: 2}},
+		{value: "-1FR", want: weekdayNum{weekday: time.Friday, n: -1}},
+		{value: "XX", err: true},
+		{value: "M", err: true},
+		{value: "0MO", err: true},
+		{value: "54MO", err: true},
+		{value: "aMO", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseWeekdayNum(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package amixr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RequestOption can be passed to any service method to customize a single request
+// e.g. client.Integrations.ListIntegrations(opt, amixr.WithContext(ctx))
+type RequestOption func(*retryablehttp.Request) error
+
+// WithContext runs the request with given context, so it can be cancelled
+// or bounded by a deadline. Context is honored while waiting for the rate limiter
+// and between retries as well.
+func WithContext(ctx context.Context) RequestOption {
+	return func(req *retryablehttp.Request) error {
+		if ctx == nil {
+			return fmt.Errorf("context required")
+		}
+		*req = *req.WithContext(ctx)
+		return nil
+	}
+}
+
+// WithHeader sets additional header on the request.
+func WithHeader(name, value string) RequestOption {
+	return func(req *retryablehttp.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	}
+}
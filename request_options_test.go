@@ -0,0 +1,84 @@
+package amixr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+func TestWithContextCancelled(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/integrations/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent with cancelled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := client.Integrations.ListIntegrations(&ListIntegrationOptions{}, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/api/v1/on_call_shifts/OH3V5FYQEYJ6M/", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err := client.OnCallShifts.UpdateOnCallShift("OH3V5FYQEYJ6M", &UpdateOnCallShiftOptions{}, WithContext(ctx))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNewRequestWithContext(t *testing.T) {
+	c, err := NewClient("token")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	req, err := c.NewRequest("GET", "test", nil, WithContext(ctx), WithHeader("X-Request-Id", "42"))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if got := req.Context().Value(ctxKey{}); got != "value" {
+		t.Errorf("request context value is %v, want %q", got, "value")
+	}
+	if got := req.Header.Get("X-Request-Id"); got != "42" {
+		t.Errorf("X-Request-Id is %q, want %q", got, "42")
+	}
+}
+
+func TestRequestOptionError(t *testing.T) {
+	c, err := NewClient("token")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	failing := func(*retryablehttp.Request) error { return fmt.Errorf("boom") }
+	if _, err := c.NewRequest("GET", "test", nil, failing); err == nil {
+		t.Error("expected error from request option")
+	}
+}
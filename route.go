@@ -2,7 +2,6 @@ package amixr
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 )
 
@@ -29,12 +28,13 @@ type PaginatedRoutesResponse struct {
 }
 
 type Route struct {
-	ID             string      `json:"id"`
-	IntegrationId  string      `json:"integration_id"`
-	Position       int         `json:"position"`
-	RoutingRegex   string      `json:"routing_regex"`
-	IsTheLastRoute bool        `json:"is_the_last_route"`
-	SlackRoute     *SlackRoute `json:"slack"`
+	ID                string      `json:"id"`
+	IntegrationId     string      `json:"integration_id"`
+	EscalationChainId *string     `json:"escalation_chain_id"`
+	Position          int         `json:"position"`
+	RoutingRegex      string      `json:"routing_regex"`
+	IsTheLastRoute    bool        `json:"is_the_last_route"`
+	SlackRoute        *SlackRoute `json:"slack"`
 }
 
 type SlackRoute struct {
@@ -43,17 +43,18 @@ type SlackRoute struct {
 
 type ListRouteOptions struct {
 	ListOptions
-	IntegrationId string `url:"integration_id,omitempty" json:"integration_id,omitempty"`
-	RoutingRegex  string `url:"routing_regex,omitempty" json:"routing_regex,omitempty"`
+	IntegrationId     string `url:"integration_id,omitempty" json:"integration_id,omitempty"`
+	RoutingRegex      string `url:"routing_regex,omitempty" json:"routing_regex,omitempty"`
+	EscalationChainId string `url:"escalation_chain_id,omitempty" json:"escalation_chain_id,omitempty"`
 }
 
 // ListRoutes gets all routes for authorized team
 //
 // http://api-docs.amixr.io/#list-routes
-func (service *RouteService) ListRoutes(opt *ListRouteOptions) (*PaginatedRoutesResponse, *http.Response, error) {
+func (service *RouteService) ListRoutes(opt *ListRouteOptions, options ...RequestOption) (*PaginatedRoutesResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -67,16 +68,57 @@ func (service *RouteService) ListRoutes(opt *ListRouteOptions) (*PaginatedRoutes
 	return routes, resp, err
 }
 
+// RouteIterator iterates over routes of all pages.
+// Use RouteService.Iter to create one.
+type RouteIterator struct {
+	pager
+	items []*Route
+}
+
+// Iter returns iterator over all routes matching opt, following pagination links
+func (service *RouteService) Iter(opt *ListRouteOptions, options ...RequestOption) *RouteIterator {
+	u := fmt.Sprintf("%s", service.url)
+	it := &RouteIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedRoutesResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Routes
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current route
+func (it *RouteIterator) Value() *Route {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllRoutes gets routes of all pages
+func (service *RouteService) ListAllRoutes(opt *ListRouteOptions, options ...RequestOption) ([]*Route, error) {
+	var routes []*Route
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		routes = append(routes, it.Value())
+	}
+	return routes, it.Err()
+}
+
 type GetRouteOptions struct {
 }
 
 // Get route by given id
 //
 // http://api-docs.amixr.io/#get-route
-func (service *RouteService) GetRoute(id string, opt *GetRouteOptions) (*Route, *http.Response, error) {
+func (service *RouteService) GetRoute(id string, opt *GetRouteOptions, options ...RequestOption) (*Route, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,20 +133,21 @@ func (service *RouteService) GetRoute(id string, opt *GetRouteOptions) (*Route,
 }
 
 type CreateRouteOptions struct {
-	IntegrationId string      `json:"integration_id,omitempty"`
-	Position      *int        `json:"position,omitempty"`
-	RoutingRegex  string      `json:"routing_regex, omitempty"`
-	Slack         *SlackRoute `json:"slack,omitempty"`
-	ManualOrder   bool        `url:"manual_order,omitempty" json:"manual_order,omitempty"`
+	IntegrationId     string      `json:"integration_id,omitempty"`
+	EscalationChainId string      `json:"escalation_chain_id,omitempty"`
+	Position          *int        `json:"position,omitempty"`
+	RoutingRegex      string      `json:"routing_regex"`
+	Slack             *SlackRoute `json:"slack,omitempty"`
+	ManualOrder       bool        `url:"manual_order,omitempty" json:"manual_order,omitempty"`
 }
 
 // Create route with given name and type
 //
 // http://api-docs.amixr.io/#create-route
-func (service *RouteService) CreateRoute(opt *CreateRouteOptions) (*Route, *http.Response, error) {
-	log.Printf("[DEBUG] create amixr route")
+func (service *RouteService) CreateRoute(opt *CreateRouteOptions, options ...RequestOption) (*Route, *http.Response, error) {
+	service.client.logger.Debug("create amixr route")
 	u := fmt.Sprintf("%s/", service.url)
-	req, err := service.client.NewRequest("POST", u, opt)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -112,8 +155,6 @@ func (service *RouteService) CreateRoute(opt *CreateRouteOptions) (*Route, *http
 	route := new(Route)
 
 	resp, err := service.client.Do(req, route)
-	log.Printf("[DEBUG] request success")
-
 	if err != nil {
 		return nil, resp, err
 	}
@@ -122,19 +163,21 @@ func (service *RouteService) CreateRoute(opt *CreateRouteOptions) (*Route, *http
 }
 
 type UpdateRouteOptions struct {
-	Position     *int        `json:"position,omitempty"`
-	Slack        *SlackRoute `json:"slack,omitempty"`
-	RoutingRegex string      `json:"routing_regex, omitempty"`
-	ManualOrder  bool        `url:"manual_order,omitempty" json:"manual_order,omitempty"`
+	// EscalationChainId isn't changed when nil, empty string unsets the chain
+	EscalationChainId *string     `json:"escalation_chain_id,omitempty"`
+	Position          *int        `json:"position,omitempty"`
+	Slack             *SlackRoute `json:"slack,omitempty"`
+	RoutingRegex      string      `json:"routing_regex"`
+	ManualOrder       bool        `url:"manual_order,omitempty" json:"manual_order,omitempty"`
 }
 
 // Updates route with new templates and/or name. At least one field in template is required
 //
 // http://api-docs.amixr.io/#update-route
-func (service *RouteService) UpdateRoute(id string, opt *UpdateRouteOptions) (*Route, *http.Response, error) {
+func (service *RouteService) UpdateRoute(id string, opt *UpdateRouteOptions, options ...RequestOption) (*Route, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("PUT", u, opt)
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -154,11 +197,11 @@ type DeleteRouteOptions struct {
 // Deletes route
 //
 // http://api-docs.amixr.io/#delete-route
-func (service *RouteService) DeleteRoute(id string, opt *DeleteRouteOptions) (*http.Response, error) {
+func (service *RouteService) DeleteRoute(id string, opt *DeleteRouteOptions, options ...RequestOption) (*http.Response, error) {
 
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("DELETE", u, opt)
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
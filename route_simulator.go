@@ -0,0 +1,419 @@
+package amixr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegexIssue is a Python regular expression construct Go regexp can't evaluate the same way
+type RegexIssue struct {
+	// Offset is byte offset of the construct in the routing regex
+	Offset    int    `json:"offset"`
+	Construct string `json:"construct"`
+	Message   string `json:"message"`
+}
+
+// RouteResult is the result of matching a single route against the alert payload
+type RouteResult struct {
+	Route *Route `json:"route"`
+	// Evaluated is false when the routing regex can't be evaluated locally, see Issues
+	Evaluated bool `json:"evaluated"`
+	// Matched reports whether the route matches the payload, even if a previous route matched first
+	Matched bool `json:"matched"`
+	// Match is the leftmost text matched by the routing regex
+	Match  string        `json:"match,omitempty"`
+	Reason string        `json:"reason"`
+	Issues []*RegexIssue `json:"issues,omitempty"`
+}
+
+// RouteSimulation is the result of routing simulation
+type RouteSimulation struct {
+	// Payload is the text routing regexes are matched against, the payload serialized the way Python json.dumps does
+	Payload string `json:"payload"`
+	// Route is the first matching route, nil if none matches
+	Route *Route `json:"route"`
+	// Uncertain is true when a route before the selected one couldn't be evaluated
+	Uncertain bool `json:"uncertain"`
+	// Results are in the order routes are evaluated, the default route last
+	Results []*RouteResult `json:"results"`
+}
+
+// SimulateRouting finds the route an alert with the payload would be routed to. Like the server,
+// it searches routing regexes of routes ordered by position in the payload serialized by Python json.dumps,
+// the default route matches any alert. Routing regexes with Python constructs which Go regexp doesn't support
+// or interprets differently aren't evaluated and make the result uncertain when they come first.
+func SimulateRouting(routes []*Route, payload []byte) (*RouteSimulation, error) {
+	text, err := pythonJSONDumps(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert payload: %v", err)
+	}
+
+	ordered := make([]*Route, len(routes))
+	copy(ordered, routes)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.IsTheLastRoute != b.IsTheLastRoute {
+			return b.IsTheLastRoute
+		}
+		return a.Position < b.Position
+	})
+
+	simulation := &RouteSimulation{Payload: text, Results: []*RouteResult{}}
+	unevaluated := false
+	for _, route := range ordered {
+		result := matchRoute(route, text)
+		if simulation.Route == nil {
+			if result.Matched {
+				simulation.Route = route
+				simulation.Uncertain = unevaluated
+			} else if !result.Evaluated {
+				unevaluated = true
+			}
+		} else if result.Matched {
+			result.Reason += fmt.Sprintf(", but route %s matches first", simulation.Route.ID)
+		}
+		simulation.Results = append(simulation.Results, result)
+	}
+	if simulation.Route == nil {
+		simulation.Uncertain = unevaluated
+	}
+	return simulation, nil
+}
+
+// SimulateRouting gets routes of the integration and finds the route an alert with the payload would be routed to,
+// see SimulateRouting
+func (service *RouteService) SimulateRouting(integrationID string, payload []byte, options ...RequestOption) (*RouteSimulation, error) {
+	routes, err := service.ListAllRoutes(&ListRouteOptions{IntegrationId: integrationID}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return SimulateRouting(routes, payload)
+}
+
+func matchRoute(route *Route, payload string) *RouteResult {
+	result := &RouteResult{Route: route}
+	if route.IsTheLastRoute {
+		result.Evaluated, result.Matched = true, true
+		result.Reason = "default route matches any alert"
+		return result
+	}
+
+	result.Issues = PythonRegexIssues(route.RoutingRegex)
+	if len(result.Issues) > 0 {
+		var constructs []string
+		for _, issue := range result.Issues {
+			constructs = append(constructs, issue.Message)
+		}
+		result.Reason = "routing regex can't be evaluated locally: " + strings.Join(constructs, "; ")
+		return result
+	}
+	re, err := regexp.Compile(route.RoutingRegex)
+	if err != nil {
+		result.Reason = fmt.Sprintf("routing regex can't be evaluated locally: %v", err)
+		return result
+	}
+
+	result.Evaluated = true
+	loc := re.FindStringIndex(payload)
+	if loc == nil {
+		result.Reason = fmt.Sprintf("routing regex %q doesn't match the payload", route.RoutingRegex)
+		return result
+	}
+	result.Matched = true
+	result.Match = payload[loc[0]:loc[1]]
+	result.Reason = fmt.Sprintf("routing regex %q matches %q at offset %d", route.RoutingRegex, result.Match, loc[0])
+	return result
+}
+
+// PythonRegexIssues finds constructs of Python regular expression which Go regexp doesn't support
+// or interprets differently: lookarounds, backreferences, conditionals, atomic groups, possessive quantifiers,
+// comments, \Z, \u escapes, omitted repetition minimum and verbose, ASCII, locale or unicode flags
+func PythonRegexIssues(pattern string) []*RegexIssue {
+	var issues []*RegexIssue
+	issue := func(offset int, construct, message string) {
+		issues = append(issues, &RegexIssue{Offset: offset, Construct: construct, Message: fmt.Sprintf("%s %q at %d", message, construct, offset)})
+	}
+	at := func(i int) byte {
+		if i < len(pattern) {
+			return pattern[i]
+		}
+		return 0
+	}
+
+	inClass := false
+	classStart := 0
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\':
+			next := at(i + 1)
+			switch {
+			case inClass:
+			case next >= '1' && next <= '9':
+				end := i + 2
+				if at(end) >= '0' && at(end) <= '9' {
+					end++
+				}
+				issue(i, pattern[i:end], "backreference")
+			case next == 'Z':
+				issue(i, `\Z`, `end of string anchor, use \z`)
+			}
+			switch {
+			case next == 'u' || next == 'U':
+				issue(i, pattern[i:i+2], "unicode escape, use \\x{...}")
+			case next == 'N' && at(i+2) == '{':
+				end := strings.IndexByte(pattern[i:], '}')
+				if end < 0 {
+					end = len(pattern) - i - 1
+				}
+				issue(i, pattern[i:i+end+1], "named unicode character")
+			}
+			i++
+		case inClass:
+			// ']' right after '[' or '[^' is a literal
+			if c == ']' && i > classStart+1 && !(i == classStart+2 && pattern[classStart+1] == '^') {
+				inClass = false
+			}
+		case c == '[':
+			inClass, classStart = true, i
+		case c == '(' && at(i+1) == '?':
+			rest := pattern[i+2:]
+			switch {
+			case strings.HasPrefix(rest, "="):
+				issue(i, "(?=", "lookahead")
+			case strings.HasPrefix(rest, "!"):
+				issue(i, "(?!", "negative lookahead")
+			case strings.HasPrefix(rest, "<="):
+				issue(i, "(?<=", "lookbehind")
+			case strings.HasPrefix(rest, "<!"):
+				issue(i, "(?<!", "negative lookbehind")
+			case strings.HasPrefix(rest, "P="):
+				issue(i, "(?P=", "named backreference")
+			case strings.HasPrefix(rest, "("):
+				issue(i, "(?(", "conditional group")
+			case strings.HasPrefix(rest, ">"):
+				issue(i, "(?>", "atomic group")
+			case strings.HasPrefix(rest, "#"):
+				issue(i, "(?#", "comment")
+			default:
+				for j := 0; j < len(rest) && strings.IndexByte("aiLmsux-", rest[j]) >= 0; j++ {
+					if strings.IndexByte("aLux", rest[j]) >= 0 {
+						issue(i+2+j, rest[j:j+1], "inline flag")
+					}
+				}
+			}
+			i++
+		case c == '{':
+			end, ok := repetitionEnd(pattern, i)
+			if !ok {
+				continue
+			}
+			if pattern[i+1] == ',' {
+				issue(i, pattern[i:end+1], "repetition without minimum")
+			}
+			if at(end+1) == '+' {
+				issue(i, pattern[i:end+2], "possessive quantifier")
+			}
+			i = end
+		case c == '*' || c == '+' || c == '?':
+			if at(i+1) == '+' {
+				issue(i, pattern[i:i+2], "possessive quantifier")
+				i++
+			} else if at(i+1) == '?' {
+				i++
+			}
+		}
+	}
+	return issues
+}
+
+// repetitionEnd returns index of '}' closing repetition {m}, {m,}, {m,n} or {,n} started at i
+func repetitionEnd(pattern string, i int) (int, bool) {
+	digits, comma := 0, false
+	for j := i + 1; j < len(pattern); j++ {
+		switch c := pattern[j]; {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == ',' && !comma:
+			comma = true
+		case c == '}':
+			return j, digits > 0
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+type pythonJSONMember struct {
+	key   string
+	value interface{}
+}
+
+// pythonJSONObject keeps members in order, a duplicated key keeps the position of its first occurrence
+type pythonJSONObject []*pythonJSONMember
+
+// pythonJSONDumps serializes JSON document the way Python json.dumps with default arguments does
+// after json.loads: keys stay in order, separators are ", " and ": ", non-ASCII characters are escaped
+// and floats are written by repr
+func pythonJSONDumps(data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodePythonJSON(decoder)
+	if err != nil {
+		return "", err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", fmt.Errorf("unexpected data after JSON value")
+	}
+	var b strings.Builder
+	writePythonJSON(&b, value)
+	return b.String(), nil
+}
+
+func decodePythonJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			list := []interface{}{}
+			for decoder.More() {
+				value, err := decodePythonJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			_, err := decoder.Token()
+			return list, err
+		}
+		object := pythonJSONObject{}
+		members := make(map[string]*pythonJSONMember)
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodePythonJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if member, ok := members[key.(string)]; ok {
+				member.value = value
+				continue
+			}
+			member := &pythonJSONMember{key: key.(string), value: value}
+			members[member.key] = member
+			object = append(object, member)
+		}
+		_, err := decoder.Token()
+		return object, err
+	default:
+		return token, nil
+	}
+}
+
+func writePythonJSON(b *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case string:
+		writePythonJSONString(b, v)
+	case json.Number:
+		b.WriteString(pythonNumber(string(v)))
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writePythonJSON(b, item)
+		}
+		b.WriteByte(']')
+	case pythonJSONObject:
+		b.WriteByte('{')
+		for i, member := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writePythonJSONString(b, member.key)
+			b.WriteString(": ")
+			writePythonJSON(b, member.value)
+		}
+		b.WriteByte('}')
+	}
+}
+
+func writePythonJSONString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r < 0x20 || r > 0x7e:
+			if r > 0xffff {
+				r -= 0x10000
+				fmt.Fprintf(b, `\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+			} else {
+				fmt.Fprintf(b, `\u%04x`, r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// pythonNumber formats JSON number the way Python does: integers as they are, floats by repr
+func pythonNumber(number string) string {
+	if !strings.ContainsAny(number, ".eE") {
+		if number == "-0" {
+			return "0"
+		}
+		return number
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		// out of float64 range, Python gives infinity
+		if strings.HasPrefix(number, "-") {
+			return "-Infinity"
+		}
+		return "Infinity"
+	}
+	exp := 0
+	if f != 0 {
+		e := strconv.FormatFloat(f, 'e', -1, 64)
+		exp, _ = strconv.Atoi(e[strings.IndexByte(e, 'e')+1:])
+	}
+	if exp < -4 || exp >= 16 {
+		return strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
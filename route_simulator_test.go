@@ -0,0 +1,178 @@
+package amixr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPythonJSONDumps(t *testing.T) {
+	payload := `{"b": 1, "a":{"x":[1.0,2.5e-7,1e16,123456789012345678901234567890,-0,1E2, true,null]},` +
+		`"b":"café 😀\n\"q\" /\u007f", "e": {}, "l": []}`
+	want := `{"b": "caf\u00e9 \ud83d\ude00\n\"q\" /\u007f", "a": {"x": [1.0, 2.5e-07, 1e+16, ` +
+		`123456789012345678901234567890, 0, 100.0, true, null]}, "e": {}, "l": []}`
+
+	got, err := pythonJSONDumps([]byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("returned\n %s\nwant\n %s", got, want)
+	}
+
+	for _, invalid := range []string{``, `{"a": }`, `{"a": 1} {}`} {
+		if _, err := pythonJSONDumps([]byte(invalid)); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestPythonRegexIssues(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		constructs []string
+	}{
+		{pattern: `"severity": "(critical|high)"`},
+		{pattern: `us-(west|east)-\d{1,2}[(?=]\[`},
+		{pattern: `(?P<region>us)-\w+(?i:prod)`},
+		{pattern: `[]\1]x*?y+?`},
+		{pattern: `^(?!.*test).*prod(?=uction)`, constructs: []string{"(?!", "(?="}},
+		{pattern: `(?<=a)b(?<!c)`, constructs: []string{"(?<=", "(?<!"}},
+		{pattern: `(a)\1 (?P<n>b)(?P=n) \12`, constructs: []string{`\1`, "(?P=", `\12`}},
+		{pattern: `(?(1)a|b)(?>c)(?#note)`, constructs: []string{"(?(", "(?>", "(?#"}},
+		{pattern: `a++b*+c?+d{2}+`, constructs: []string{"++", "*+", "?+", "{2}+"}},
+		{pattern: `(?xi) a{,3} \Z`, constructs: []string{"x", "{,3}", `\Z`}},
+		{pattern: `\u00e9\N{DASH}`, constructs: []string{`\u`, `\N{DASH}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var constructs []string
+			for _, issue := range PythonRegexIssues(tt.pattern) {
+				constructs = append(constructs, issue.Construct)
+			}
+			if !reflect.DeepEqual(tt.constructs, constructs) {
+				t.Errorf("constructs are %q, want %q", constructs, tt.constructs)
+			}
+		})
+	}
+}
+
+func testSimulatorRoutes() []*Route {
+	return []*Route{
+		{ID: "DEFAULT", Position: 0, IsTheLastRoute: true},
+		{ID: "STAGING", Position: 2, RoutingRegex: `"env": "staging"`},
+		{ID: "CRITICAL", Position: 1, RoutingRegex: `"severity": "(critical|high)"`},
+		{ID: "LOOKAHEAD", Position: 3, RoutingRegex: `"env": "(?!staging)`},
+	}
+}
+
+func TestSimulateRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		routes    []*Route
+		payload   string
+		route     string
+		uncertain bool
+		order     []string
+		matched   []bool
+		reasons   []string
+	}{
+		{
+			name:    "first matching route by position",
+			routes:  testSimulatorRoutes(),
+			payload: `{"severity":"critical","env":"staging"}`,
+			route:   "CRITICAL",
+			order:   []string{"CRITICAL", "STAGING", "LOOKAHEAD", "DEFAULT"},
+			matched: []bool{true, true, false, true},
+			reasons: []string{
+				`routing regex "\"severity\": \"(critical|high)\"" matches "\"severity\": \"critical\"" at offset 1`,
+				`routing regex "\"env\": \"staging\"" matches "\"env\": \"staging\"" at offset 25, but route CRITICAL matches first`,
+				`routing regex can't be evaluated locally: negative lookahead "(?!" at 8`,
+				`default route matches any alert, but route CRITICAL matches first`,
+			},
+		},
+		{
+			name:      "default route after unsupported regex",
+			routes:    testSimulatorRoutes(),
+			payload:   `{"severity":"low","env":"production"}`,
+			route:     "DEFAULT",
+			uncertain: true,
+			order:     []string{"CRITICAL", "STAGING", "LOOKAHEAD", "DEFAULT"},
+			matched:   []bool{false, false, false, true},
+			reasons: []string{
+				`routing regex "\"severity\": \"(critical|high)\"" doesn't match the payload`,
+				`routing regex "\"env\": \"staging\"" doesn't match the payload`,
+				`routing regex can't be evaluated locally: negative lookahead "(?!" at 8`,
+				`default route matches any alert`,
+			},
+		},
+		{
+			name:    "invalid regex and no default route",
+			routes:  []*Route{{ID: "INVALID", RoutingRegex: `(unclosed`}},
+			payload: `{}`,
+			order:   []string{"INVALID"},
+			matched: []bool{false},
+			reasons: []string{
+				"routing regex can't be evaluated locally: error parsing regexp: missing closing ): `(unclosed`",
+			},
+			uncertain: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulation, err := SimulateRouting(tt.routes, []byte(tt.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			route := ""
+			if simulation.Route != nil {
+				route = simulation.Route.ID
+			}
+			if route != tt.route || simulation.Uncertain != tt.uncertain {
+				t.Errorf("route is %q uncertain %t, want %q uncertain %t", route, simulation.Uncertain, tt.route, tt.uncertain)
+			}
+			var order, reasons []string
+			var matched []bool
+			for _, result := range simulation.Results {
+				order = append(order, result.Route.ID)
+				matched = append(matched, result.Matched)
+				reasons = append(reasons, result.Reason)
+			}
+			if !reflect.DeepEqual(tt.order, order) || !reflect.DeepEqual(tt.matched, matched) {
+				t.Errorf("routes %v matched %v, want %v matched %v", order, matched, tt.order, tt.matched)
+			}
+			if !reflect.DeepEqual(tt.reasons, reasons) {
+				t.Errorf("reasons are\n %q\nwant\n %q", reasons, tt.reasons)
+			}
+		})
+	}
+
+	if _, err := SimulateRouting(testSimulatorRoutes(), []byte(`not json`)); err == nil {
+		t.Error("expected error for invalid payload")
+	}
+}
+
+func TestRouteSimulateRouting(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/routes", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if got := r.URL.Query().Get("integration_id"); got != "CGEXJ922S7TXQ" {
+			t.Errorf("integration_id is %q, want %q", got, "CGEXJ922S7TXQ")
+		}
+		fmt.Fprintf(w, `{"count": 1, "next": null, "previous": null, "results": [%s]}`, testRouteBody)
+	})
+
+	simulation, err := client.Routes.SimulateRouting("CGEXJ922S7TXQ", []byte(`{"region": "us-west-1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(testRoute, simulation.Route) {
+		t.Errorf("returned\n %+v\nwant\n %+v", simulation.Route, testRoute)
+	}
+	if simulation.Payload != `{"region": "us-west-1"}` {
+		t.Errorf("payload is %s", simulation.Payload)
+	}
+}
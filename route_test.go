@@ -1,6 +1,7 @@
 package amixr
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -9,12 +10,15 @@ import (
 
 var testSlackChannelId = "TEST_SLACK_CHANNEL_ID"
 
+var testRouteEscalationChainId = "F5JU6KJET33FE"
+
 var testRoute = &Route{
-	ID:             "RH2V5FYIPYJ1M",
-	IntegrationId:  "CGEXJ922S7TXQ",
-	Position:       0,
-	RoutingRegex:   "us-west",
-	IsTheLastRoute: false,
+	ID:                "RH2V5FYIPYJ1M",
+	IntegrationId:     "CGEXJ922S7TXQ",
+	EscalationChainId: &testRouteEscalationChainId,
+	Position:          0,
+	RoutingRegex:      "us-west",
+	IsTheLastRoute:    false,
 	SlackRoute: &SlackRoute{
 		&testSlackChannelId,
 	},
@@ -23,6 +27,7 @@ var testRoute = &Route{
 var testRouteBody = `{
 	"id": "RH2V5FYIPYJ1M",
 	"integration_id": "CGEXJ922S7TXQ",
+	"escalation_chain_id": "F5JU6KJET33FE",
 	"routing_regex": "us-west",
 	"position": 0,
 	"is_the_last_route": false,
@@ -133,3 +138,29 @@ func TestGetRoute(t *testing.T) {
 		t.Errorf("returned\n %+v\n want\n %+v\n", route, want)
 	}
 }
+
+func TestUpdateRouteUnsetEscalationChain(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/routes/RH2V5FYIPYJ1M/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "PUT")
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if chainID, ok := body["escalation_chain_id"]; !ok || chainID != "" {
+			t.Errorf("escalation chain isn't unset, got %+v", body)
+		}
+		fmt.Fprint(w, testRouteBody)
+	})
+
+	unset := ""
+	options := &UpdateRouteOptions{
+		EscalationChainId: &unset,
+		RoutingRegex:      "us-west",
+	}
+	if _, _, err := client.Routes.UpdateRoute("RH2V5FYIPYJ1M", options); err != nil {
+		t.Fatal(err)
+	}
+}
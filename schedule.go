@@ -2,8 +2,8 @@ package amixr
 
 import (
 	"fmt"
-	"log"
 	"net/http"
+	"time"
 )
 
 // Handles requests to schedule endpoint
@@ -23,6 +23,12 @@ func NewScheduleService(client *Client) *ScheduleService {
 	return &scheduleService
 }
 
+// Schedule types
+const (
+	ScheduleTypeCalendar = "calendar"
+	ScheduleTypeICal     = "ical"
+)
+
 type PaginatedSchedulesResponse struct {
 	PaginatedResponse
 	Schedules []*Schedule `json:"results"`
@@ -38,6 +44,44 @@ type Schedule struct {
 	Slack     *SlackSchedule `json:"slack"`
 }
 
+// Location loads schedule time zone. Schedule without time zone is in UTC.
+func (schedule *Schedule) Location() (*time.Location, error) {
+	if schedule.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q of schedule %s: %w", schedule.TimeZone, schedule.ID, err)
+	}
+	return loc, nil
+}
+
+// Window is a half-open time interval [Start, End)
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (window Window) validate() error {
+	if window.Start.IsZero() || window.End.IsZero() {
+		return fmt.Errorf("window start and end required")
+	}
+	if !window.End.After(window.Start) {
+		return fmt.Errorf("window end %s is not after start %s", window.End, window.Start)
+	}
+	return nil
+}
+
+// Overlaps reports whether [start, end) intersects the window
+func (window Window) Overlaps(start, end time.Time) bool {
+	return start.Before(window.End) && end.After(window.Start)
+}
+
+// Contains reports whether t is within the window
+func (window Window) Contains(t time.Time) bool {
+	return !t.Before(window.Start) && t.Before(window.End)
+}
+
 type SlackSchedule struct {
 	ChannelId *string `json:"channel_id"`
 }
@@ -50,10 +94,10 @@ type ListScheduleOptions struct {
 // ListSchedules gets all schedules for authorized team
 //
 // http://api-docs.amixr.io/#list-schedules
-func (service *ScheduleService) ListSchedules(opt *ListScheduleOptions) (*PaginatedSchedulesResponse, *http.Response, error) {
+func (service *ScheduleService) ListSchedules(opt *ListScheduleOptions, options ...RequestOption) (*PaginatedSchedulesResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s/", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -67,14 +111,55 @@ func (service *ScheduleService) ListSchedules(opt *ListScheduleOptions) (*Pagina
 	return schedules, resp, err
 }
 
+// ScheduleIterator iterates over schedules of all pages.
+// Use ScheduleService.Iter to create one.
+type ScheduleIterator struct {
+	pager
+	items []*Schedule
+}
+
+// Iter returns iterator over all schedules matching opt, following pagination links
+func (service *ScheduleService) Iter(opt *ListScheduleOptions, options ...RequestOption) *ScheduleIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &ScheduleIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedSchedulesResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Schedules
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current schedule
+func (it *ScheduleIterator) Value() *Schedule {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllSchedules gets schedules of all pages
+func (service *ScheduleService) ListAllSchedules(opt *ListScheduleOptions, options ...RequestOption) ([]*Schedule, error) {
+	var schedules []*Schedule
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		schedules = append(schedules, it.Value())
+	}
+	return schedules, it.Err()
+}
+
 type GetScheduleOptions struct {
 }
 
 // Get schedule shift by given id
-func (service *ScheduleService) GetSchedule(id string, opt *GetScheduleOptions) (*Schedule, *http.Response, error) {
+func (service *ScheduleService) GetSchedule(id string, opt *GetScheduleOptions, options ...RequestOption) (*Schedule, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -96,10 +181,10 @@ type CreateScheduleOptions struct {
 }
 
 // Create schedule with given name
-func (service *ScheduleService) CreateSchedule(opt *CreateScheduleOptions) (*Schedule, *http.Response, error) {
-	log.Printf("[DEBUG] create amixr schedule")
+func (service *ScheduleService) CreateSchedule(opt *CreateScheduleOptions, options ...RequestOption) (*Schedule, *http.Response, error) {
+	service.client.logger.Debug("create amixr schedule")
 	u := fmt.Sprintf("%s/", service.url)
-	req, err := service.client.NewRequest("POST", u, opt)
+	req, err := service.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -123,10 +208,10 @@ type UpdateScheduleOptions struct {
 }
 
 // Updates schedule
-func (service *ScheduleService) UpdateSchedule(id string, opt *UpdateScheduleOptions) (*Schedule, *http.Response, error) {
+func (service *ScheduleService) UpdateSchedule(id string, opt *UpdateScheduleOptions, options ...RequestOption) (*Schedule, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("PUT", u, opt)
+	req, err := service.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -144,11 +229,11 @@ type DeleteScheduleOptions struct {
 }
 
 // Deletes schedule
-func (service *ScheduleService) DeleteSchedule(id string, opt *DeleteScheduleOptions) (*http.Response, error) {
+func (service *ScheduleService) DeleteSchedule(id string, opt *DeleteScheduleOptions, options ...RequestOption) (*http.Response, error) {
 
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("DELETE", u, opt)
+	req, err := service.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
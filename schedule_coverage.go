@@ -0,0 +1,372 @@
+package amixr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Coverage finding kinds
+const (
+	// CoverageGap is time within the window when nobody is on call
+	CoverageGap = "gap"
+	// CoverageOverlap is time when shifts of the same level are on call together
+	CoverageOverlap = "overlap"
+	// CoverageUnknownUser is a shift user which doesn't exist
+	CoverageUnknownUser = "unknown_user"
+	// CoverageEmptyShift is a shift without users
+	CoverageEmptyShift = "empty_shift"
+)
+
+// Coverage finding severities
+const (
+	CoverageSeverityError   = "error"
+	CoverageSeverityWarning = "warning"
+)
+
+// CoverageOptions configures schedule coverage analysis
+type CoverageOptions struct {
+	// Window is the analyzed period of time, required
+	Window Window
+	// MinGap is the shortest uncovered time reported as gap, all gaps are reported by default
+	MinGap time.Duration
+	// Users are all existing users, shift users are not checked if it is nil.
+	// ScheduleService.AnalyzeCoverage gets all users when it is nil.
+	Users []*User
+}
+
+// CoverageFinding is a problem of schedule coverage
+type CoverageFinding struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Shifts are ids of on-call shifts or UIDs of iCal events the finding is about
+	Shifts []string `json:"shifts,omitempty"`
+	User   string   `json:"user,omitempty"`
+	Level  int      `json:"level,omitempty"`
+	// Start and End are set for gaps and overlaps
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// CoverageReport is the result of schedule coverage analysis
+type CoverageReport struct {
+	ScheduleId string             `json:"schedule_id,omitempty"`
+	Start      time.Time          `json:"start"`
+	End        time.Time          `json:"end"`
+	Findings   []*CoverageFinding `json:"findings"`
+}
+
+// HasErrors reports whether any finding has error severity: a gap or an unknown user
+func (report *CoverageReport) HasErrors() bool {
+	for _, finding := range report.Findings {
+		if finding.Severity == CoverageSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeCoverage finds gaps and same level overlaps of on-call shifts within the window,
+// shifts with unknown users and shifts without users. Overlapping overrides are not reported
+// as they replace other shifts anyway. iCal events are checked only when they are on call within the window,
+// unknown summary words are warnings unless they are emails. Findings without time come first,
+// the rest are ordered by start.
+func AnalyzeCoverage(source OnCallSource, opt *CoverageOptions) (*CoverageReport, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("coverage options required")
+	}
+	occurrences, err := source.Occurrences(opt.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CoverageReport{Start: opt.Window.Start, End: opt.Window.End, Findings: []*CoverageFinding{}}
+	switch s := source.(type) {
+	case *OnCallCalculator:
+		report.ScheduleId = s.schedule.ID
+		for _, rule := range s.rules {
+			report.Findings = append(report.Findings, shiftFindings(rule.shift, opt.Users)...)
+		}
+	case *ICalCalendar:
+		// only events on call within the window are checked, feeds often keep years of history
+		var events []*ICalEvent
+		for _, occurrence := range occurrences {
+			if !containsEvent(events, occurrence.Event) {
+				events = append(events, occurrence.Event)
+			}
+		}
+		for _, event := range events {
+			report.Findings = append(report.Findings, eventFindings(event)...)
+		}
+	}
+
+	report.Findings = append(report.Findings, gapFindings(resolveOnCallIntervals(occurrences, opt.Window), opt)...)
+	report.Findings = append(report.Findings, overlapFindings(occurrences, opt.Window)...)
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if (a.Start == nil) != (b.Start == nil) {
+			return a.Start == nil
+		}
+		if a.Start != nil && !a.Start.Equal(*b.Start) {
+			return a.Start.Before(*b.Start)
+		}
+		return a.Kind < b.Kind
+	})
+	return report, nil
+}
+
+// AnalyzeCoverage gets the schedule with its on-call shifts, or its iCal feed for ical schedules,
+// and analyzes its coverage, see AnalyzeCoverage
+func (service *ScheduleService) AnalyzeCoverage(scheduleID string, opt *CoverageOptions, options ...RequestOption) (*CoverageReport, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("coverage options required")
+	}
+	analyze := *opt
+	if analyze.Users == nil {
+		users, err := service.client.Users.ListAllUsers(&ListUserOptions{}, options...)
+		if err != nil {
+			return nil, err
+		}
+		analyze.Users = users
+	}
+
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := service.onCallSource(schedule, analyze.Users, options)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := AnalyzeCoverage(source, &analyze)
+	if err != nil {
+		return nil, err
+	}
+	report.ScheduleId = schedule.ID
+	return report, nil
+}
+
+// onCallSource creates OnCallSource of the schedule, parsing iCal feed of ical schedules with given users
+func (service *ScheduleService) onCallSource(schedule *Schedule, users []*User, options []RequestOption) (OnCallSource, error) {
+	if schedule.Type == ScheduleTypeICal {
+		feed, err := service.downloadICal(schedule, options)
+		if err != nil {
+			return nil, err
+		}
+		return ParseICal(feed, users)
+	}
+	shifts, err := service.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: schedule.ID}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return NewOnCallCalculator(schedule, shifts)
+}
+
+func shiftFindings(shift *OnCallShift, users []*User) []*CoverageFinding {
+	var findings []*CoverageFinding
+	empty := func(message string) {
+		findings = append(findings, &CoverageFinding{
+			Kind:     CoverageEmptyShift,
+			Severity: CoverageSeverityWarning,
+			Message:  message,
+			Shifts:   []string{shift.ID},
+			Level:    shift.Level,
+		})
+	}
+
+	var shiftUsers []string
+	if shift.Type == OnCallShiftTypeRollingUsers {
+		if shift.RollingUsers == nil || len(*shift.RollingUsers) == 0 {
+			empty(fmt.Sprintf("on-call shift %s has no rolling users", shift.ID))
+		} else {
+			for i, group := range *shift.RollingUsers {
+				if len(group) == 0 {
+					empty(fmt.Sprintf("rolling users group %d of on-call shift %s is empty", i+1, shift.ID))
+				}
+				shiftUsers = append(shiftUsers, group...)
+			}
+		}
+	} else {
+		if shift.Users == nil || len(*shift.Users) == 0 {
+			empty(fmt.Sprintf("on-call shift %s has no users", shift.ID))
+		} else {
+			shiftUsers = *shift.Users
+		}
+	}
+
+	if users == nil {
+		return findings
+	}
+	known := make(map[string]bool)
+	for _, user := range users {
+		known[user.ID] = true
+	}
+	var reported []string
+	for _, user := range shiftUsers {
+		if known[user] || containsString(reported, user) {
+			continue
+		}
+		reported = append(reported, user)
+		findings = append(findings, &CoverageFinding{
+			Kind:     CoverageUnknownUser,
+			Severity: CoverageSeverityError,
+			Message:  fmt.Sprintf("on-call shift %s references unknown user %s", shift.ID, user),
+			Shifts:   []string{shift.ID},
+			User:     user,
+			Level:    shift.Level,
+		})
+	}
+	return findings
+}
+
+func eventFindings(event *ICalEvent) []*CoverageFinding {
+	var findings []*CoverageFinding
+	if len(event.Users) == 0 {
+		findings = append(findings, &CoverageFinding{
+			Kind:     CoverageEmptyShift,
+			Severity: CoverageSeverityWarning,
+			Message:  fmt.Sprintf("iCal event %s %q has no known users", event.UID, event.Summary),
+			Shifts:   []string{event.UID},
+			Level:    event.Level,
+		})
+	}
+	for _, name := range event.UnknownUsers {
+		// emails are meant to be users, other words may be just part of the summary
+		severity := CoverageSeverityWarning
+		if strings.Contains(name, "@") && !strings.HasPrefix(name, "@") {
+			severity = CoverageSeverityError
+		}
+		findings = append(findings, &CoverageFinding{
+			Kind:     CoverageUnknownUser,
+			Severity: severity,
+			Message:  fmt.Sprintf("iCal event %s references unknown user %s", event.UID, name),
+			Shifts:   []string{event.UID},
+			User:     name,
+			Level:    event.Level,
+		})
+	}
+	return findings
+}
+
+func gapFindings(intervals []*OnCallInterval, opt *CoverageOptions) []*CoverageFinding {
+	var findings []*CoverageFinding
+	gap := func(start, end time.Time) {
+		if !end.After(start) || end.Sub(start) < opt.MinGap {
+			return
+		}
+		findings = append(findings, &CoverageFinding{
+			Kind:     CoverageGap,
+			Severity: CoverageSeverityError,
+			Message:  fmt.Sprintf("nobody is on call from %s to %s", start.Format(time.RFC3339), end.Format(time.RFC3339)),
+			Start:    &start,
+			End:      &end,
+		})
+	}
+
+	covered := opt.Window.Start
+	for _, interval := range intervals {
+		gap(covered, interval.Start)
+		covered = interval.End
+	}
+	gap(covered, opt.Window.End)
+	return findings
+}
+
+func overlapFindings(occurrences []*ShiftOccurrence, window Window) []*CoverageFinding {
+	var findings []*CoverageFinding
+	last := make(map[string]*CoverageFinding)
+	for i, a := range occurrences {
+		if a.Override || len(a.Users) == 0 {
+			continue
+		}
+		for _, b := range occurrences[i+1:] {
+			if !b.Start.Before(a.End) {
+				break
+			}
+			if b.Override || len(b.Users) == 0 || b.Level != a.Level {
+				continue
+			}
+			start, end := latest(a.Start, b.Start, window.Start), earliest(a.End, b.End, window.End)
+			if !end.After(start) {
+				continue
+			}
+
+			shifts := []string{occurrenceSourceID(a), occurrenceSourceID(b)}
+			sort.Strings(shifts)
+			key := fmt.Sprintf("%d/%s", a.Level, strings.Join(shifts, "/"))
+			if finding, ok := last[key]; ok && !start.After(*finding.End) {
+				if end.After(*finding.End) {
+					finding.End = &end
+				}
+				finding.Message = overlapMessage(finding)
+				continue
+			}
+
+			finding := &CoverageFinding{
+				Kind:     CoverageOverlap,
+				Severity: CoverageSeverityWarning,
+				Shifts:   shifts,
+				Level:    a.Level,
+				Start:    &start,
+				End:      &end,
+			}
+			finding.Message = overlapMessage(finding)
+			last[key] = finding
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func overlapMessage(finding *CoverageFinding) string {
+	what := "shift " + finding.Shifts[0] + " overlaps itself"
+	if finding.Shifts[0] != finding.Shifts[1] {
+		what = "shifts " + strings.Join(finding.Shifts, " and ") + " overlap"
+	}
+	return fmt.Sprintf("level %d %s from %s to %s", finding.Level, what, finding.Start.Format(time.RFC3339), finding.End.Format(time.RFC3339))
+}
+
+// occurrenceSourceID returns id of the on-call shift or UID of the iCal event of the occurrence
+func occurrenceSourceID(occurrence *ShiftOccurrence) string {
+	if occurrence.Shift != nil {
+		return occurrence.Shift.ID
+	}
+	if occurrence.Event != nil {
+		return occurrence.Event.UID
+	}
+	return ""
+}
+
+func latest(times ...time.Time) time.Time {
+	result := times[0]
+	for _, t := range times[1:] {
+		if t.After(result) {
+			result = t
+		}
+	}
+	return result
+}
+
+func earliest(times ...time.Time) time.Time {
+	result := times[0]
+	for _, t := range times[1:] {
+		if t.Before(result) {
+			result = t
+		}
+	}
+	return result
+}
+
+func containsEvent(events []*ICalEvent, event *ICalEvent) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package amixr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCoverageUsers(ids ...string) []*User {
+	var users []*User
+	for _, id := range ids {
+		users = append(users, &User{ID: id, Username: strings.ToLower(id)})
+	}
+	return users
+}
+
+func formatFindings(findings []*CoverageFinding) []string {
+	var result []string
+	for _, f := range findings {
+		s := fmt.Sprintf("%s %s %v %s L%d", f.Kind, f.Severity, f.Shifts, f.User, f.Level)
+		if f.Start != nil {
+			s += fmt.Sprintf(" %s-%s", f.Start.UTC().Format("01-02T15:04"), f.End.UTC().Format("01-02T15:04"))
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestAnalyzeCoverage(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, testCalculatorShifts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := Window{Start: time.Date(2020, 9, 7, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 9, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		opt      *CoverageOptions
+		findings []string
+		errors   bool
+	}{
+		{
+			name: "all findings",
+			opt:  &CoverageOptions{Window: window, Users: testCoverageUsers("A", "B", "C", "D", "F")},
+			findings: []string{
+				"empty_shift warning [empty]  L5",
+				"unknown_user error [pair] E L0",
+				"gap error []  L0 09-07T00:00-09-07T09:00",
+				"gap error []  L0 09-07T21:00-09-08T09:00",
+				"overlap warning [pair primary]  L0 09-08T10:00-09-08T11:00",
+				"gap error []  L0 09-08T21:00-09-09T00:00",
+			},
+			errors: true,
+		},
+		{
+			name: "short gaps and users not checked",
+			opt:  &CoverageOptions{Window: window, MinGap: 4 * time.Hour},
+			findings: []string{
+				"empty_shift warning [empty]  L5",
+				"gap error []  L0 09-07T00:00-09-07T09:00",
+				"gap error []  L0 09-07T21:00-09-08T09:00",
+				"overlap warning [pair primary]  L0 09-08T10:00-09-08T11:00",
+			},
+			errors: true,
+		},
+		{
+			name: "warnings only",
+			opt: &CoverageOptions{
+				Window: Window{Start: time.Date(2020, 9, 8, 10, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 8, 12, 0, 0, 0, time.UTC)},
+				Users:  testCoverageUsers("A", "B", "C", "D", "E", "F"),
+			},
+			findings: []string{
+				"empty_shift warning [empty]  L5",
+				"overlap warning [pair primary]  L0 09-08T10:00-09-08T11:00",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := AnalyzeCoverage(calculator, tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.ScheduleId != "SBM7DV7BKFUYU" {
+				t.Errorf("schedule id is %q, want %q", report.ScheduleId, "SBM7DV7BKFUYU")
+			}
+			if got := formatFindings(report.Findings); !reflect.DeepEqual(tt.findings, got) {
+				t.Errorf("findings are\n %v\nwant\n %v", strings.Join(got, "\n "), strings.Join(tt.findings, "\n "))
+			}
+			if report.HasErrors() != tt.errors {
+				t.Errorf("has errors is %t, want %t", report.HasErrors(), tt.errors)
+			}
+		})
+	}
+
+	if _, err := AnalyzeCoverage(calculator, nil); err == nil {
+		t.Error("expected error without options")
+	}
+}
+
+func TestAnalyzeCoverageOverlaps(t *testing.T) {
+	daily := FrequencyDaily
+	shift := func(id string, duration int, users ...string) *OnCallShift {
+		s := testCalculatorShift(id, OnCallShiftTypeRecurrentEvent, "2020-09-07T00:00:00", duration, 0, users...)
+		s.Frequency = &daily
+		return s
+	}
+	rolling := testWeekly(testCalculatorShift("rolling", OnCallShiftTypeRollingUsers, "2020-09-07T00:00:00", 3600, 0))
+	rolling.Users = nil
+	rolling.RollingUsers = &[][]string{{"A"}, {}}
+
+	tests := []struct {
+		name     string
+		shifts   []*OnCallShift
+		findings []string
+	}{
+		{
+			name:   "contiguous overlaps merged",
+			shifts: []*OnCallShift{shift("first", 24*3600, "A"), shift("second", 24*3600, "B")},
+			findings: []string{
+				"overlap warning [first second]  L0 09-07T00:00-09-10T00:00",
+			},
+		},
+		{
+			name:   "shift overlapping itself",
+			shifts: []*OnCallShift{shift("long", 25*3600, "A")},
+			findings: []string{
+				"overlap warning [long long]  L0 09-08T00:00-09-08T01:00",
+				"overlap warning [long long]  L0 09-09T00:00-09-09T01:00",
+			},
+		},
+		{
+			name:   "empty rolling group",
+			shifts: []*OnCallShift{shift("all", 24*3600, "A"), rolling},
+			findings: []string{
+				"empty_shift warning [rolling]  L0",
+				"overlap warning [all rolling]  L0 09-07T00:00-09-07T01:00",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, tt.shifts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := AnalyzeCoverage(calculator, &CoverageOptions{
+				Window: Window{Start: time.Date(2020, 9, 7, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := formatFindings(report.Findings); !reflect.DeepEqual(tt.findings, got) {
+				t.Errorf("findings are\n %v\nwant\n %v", strings.Join(got, "\n "), strings.Join(tt.findings, "\n "))
+			}
+		})
+	}
+}
+
+func TestAnalyzeCoverageICal(t *testing.T) {
+	calendar, err := ParseICal(strings.NewReader(testICalFeed), testICalUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := AnalyzeCoverage(calendar, &CoverageOptions{
+		Window: Window{Start: time.Date(2020, 9, 7, 7, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 7, 19, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the weekend event with unknown ghost isn't on call within the window
+	want := []string{
+		"unknown_user warning [night@example.com] dave L0",
+		"gap error []  L0 09-07T15:00-09-07T17:00",
+	}
+	if got := formatFindings(report.Findings); !reflect.DeepEqual(want, got) {
+		t.Errorf("findings are\n %v\nwant\n %v", strings.Join(got, "\n "), strings.Join(want, "\n "))
+	}
+
+	feed := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:primary",
+		"DTSTART:20200907T070000Z",
+		"DTEND:20200907T190000Z",
+		"SUMMARY:On call: alex (Primary)\\, ghost@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	calendar, err = ParseICal(strings.NewReader(feed), testICalUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = AnalyzeCoverage(calendar, &CoverageOptions{
+		Window: Window{Start: time.Date(2020, 9, 7, 7, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 7, 19, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"unknown_user error [primary] ghost@example.com L0"}
+	if got := formatFindings(report.Findings); !reflect.DeepEqual(want, got) {
+		t.Errorf("findings are\n %v\nwant\n %v", strings.Join(got, "\n "), strings.Join(want, "\n "))
+	}
+}
+
+func TestScheduleAnalyzeCoverage(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []}`)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [{
+			"id": "OH3V5FYQEYJ6M",
+			"schedule_id": "SBM7DV7BKFUYU",
+			"type": "recurrent_event",
+			"name": "Nights",
+			"level": 0,
+			"start": "2020-09-04T20:00:00",
+			"duration": 43200,
+			"frequency": "daily",
+			"interval": 1,
+			"users": ["U4DNY931HHJS5", "UDELETED"]
+		}]}`)
+	})
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserBody)
+	})
+
+	report, err := client.Schedules.AnalyzeCoverage("SBM7DV7BKFUYU", &CoverageOptions{
+		Window: Window{Start: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schedule_id":"SBM7DV7BKFUYU","start":"2020-09-10T00:00:00Z","end":"2020-09-11T00:00:00Z","findings":[` +
+		`{"kind":"unknown_user","severity":"error","message":"on-call shift OH3V5FYQEYJ6M references unknown user UDELETED","shifts":["OH3V5FYQEYJ6M"],"user":"UDELETED"},` +
+		`{"kind":"gap","severity":"error","message":"nobody is on call from 2020-09-10T08:00:00Z to 2020-09-10T20:00:00Z","start":"2020-09-10T08:00:00Z","end":"2020-09-10T20:00:00Z"}]}`
+	if string(data) != want {
+		t.Errorf("returned\n %s\nwant\n %s", data, want)
+	}
+}
@@ -0,0 +1,315 @@
+package amixr
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Default night hours of LoadReportOptions, in the schedule time zone
+const (
+	DefaultNightStart = 22 * time.Hour
+	DefaultNightEnd   = 7 * time.Hour
+)
+
+// LoadReportOptions configures on-call load report
+type LoadReportOptions struct {
+	// Window is the reported period of time, required
+	Window Window
+	// NightStart and NightEnd are times of day in the schedule time zone bounding night hours,
+	// night may wrap over midnight. DefaultNightStart and DefaultNightEnd are used if both are zero.
+	NightStart time.Duration
+	NightEnd   time.Duration
+	// Weekend days, Saturday and Sunday by default
+	Weekend []time.Weekday
+	// Holidays are dates of public holidays, only year, month and day are used
+	Holidays []time.Time
+	// AlertGroups are joined to count incidents per user, if given
+	AlertGroups []*AlertGroup
+	// Users are used to add usernames to the report.
+	// ScheduleService.LoadReport gets all users when it is nil.
+	Users []*User
+	// AlertGroupsFilter makes ScheduleService.LoadReport get alert groups matching it,
+	// started within the window unless the filter sets StartedAt
+	AlertGroupsFilter *ListAlertGroupOptions
+}
+
+// UserLoad is on-call load of a single user. Weekend, night and holiday hours
+// are parts of total hours and may overlap each other, e.g. a weekend night.
+type UserLoad struct {
+	User     string `json:"user"`
+	Username string `json:"username,omitempty"`
+	// Periods is number of separate on-call periods, adjacent shifts make a single period
+	Periods      int     `json:"periods"`
+	Hours        float64 `json:"hours"`
+	WeekendHours float64 `json:"weekend_hours"`
+	NightHours   float64 `json:"night_hours"`
+	HolidayHours float64 `json:"holiday_hours"`
+	// Incidents is number of alert groups started while the user was on call
+	Incidents int `json:"incidents"`
+	// ResolvedIncidents is number of alert groups resolved by the user within the window
+	ResolvedIncidents int `json:"resolved_incidents"`
+}
+
+// LoadReport is on-call load of schedule users, ordered by hours, most loaded first
+type LoadReport struct {
+	ScheduleId string      `json:"schedule_id,omitempty"`
+	TimeZone   string      `json:"time_zone"`
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`
+	Users      []*UserLoad `json:"users"`
+}
+
+type userLoad struct {
+	load                           *UserLoad
+	total, weekend, night, holiday time.Duration
+}
+
+// NewLoadReport totals hours users are on call within the window
+func NewLoadReport(source OnCallSource, opt *LoadReportOptions) (*LoadReport, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("load report options required")
+	}
+	nightStart, nightEnd := opt.NightStart, opt.NightEnd
+	if nightStart == 0 && nightEnd == 0 {
+		nightStart, nightEnd = DefaultNightStart, DefaultNightEnd
+	}
+	if nightStart < 0 || nightStart >= 24*time.Hour || nightEnd < 0 || nightEnd >= 24*time.Hour {
+		return nil, fmt.Errorf("night hours must be within a day")
+	}
+	intervals, err := source.OnCallIntervals(opt.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := source.Location()
+	weekend := opt.Weekend
+	if weekend == nil {
+		weekend = []time.Weekday{time.Saturday, time.Sunday}
+	}
+	holidays := make(map[time.Time]bool)
+	for _, holiday := range opt.Holidays {
+		holidays[civilDate(holiday)] = true
+	}
+
+	loads := make(map[string]*userLoad)
+	get := func(user string) *userLoad {
+		l, ok := loads[user]
+		if !ok {
+			l = &userLoad{load: &UserLoad{User: user}}
+			loads[user] = l
+		}
+		return l
+	}
+
+	lastEnd := make(map[string]time.Time)
+	for _, interval := range intervals {
+		for _, user := range interval.Users {
+			if end, ok := lastEnd[user]; !ok || !end.Equal(interval.Start) {
+				get(user).load.Periods++
+			}
+			lastEnd[user] = interval.End
+		}
+		splitByDayTime(interval.Start, interval.End, loc, nightStart, nightEnd, func(start, end time.Time) {
+			d := end.Sub(start)
+			local := start.In(loc)
+			isWeekend := containsWeekday(weekend, local.Weekday())
+			isHoliday := holidays[civilDate(local)]
+			isNight := inNight(local, nightStart, nightEnd)
+			for _, user := range interval.Users {
+				l := get(user)
+				l.total += d
+				if isWeekend {
+					l.weekend += d
+				}
+				if isNight {
+					l.night += d
+				}
+				if isHoliday {
+					l.holiday += d
+				}
+			}
+		})
+	}
+
+	for _, alertGroup := range opt.AlertGroups {
+		if started, err := time.Parse(time.RFC3339Nano, alertGroup.CreatedAt); err == nil && opt.Window.Contains(started) {
+			for _, interval := range intervals {
+				if (Window{Start: interval.Start, End: interval.End}).Contains(started) {
+					for _, user := range interval.Users {
+						get(user).load.Incidents++
+					}
+				}
+			}
+		}
+		if alertGroup.ResolvedBy == nil || alertGroup.ResolvedAt == nil || *alertGroup.ResolvedBy == "" {
+			continue
+		}
+		if resolved, err := time.Parse(time.RFC3339Nano, *alertGroup.ResolvedAt); err == nil && opt.Window.Contains(resolved) {
+			get(*alertGroup.ResolvedBy).load.ResolvedIncidents++
+		}
+	}
+
+	usernames := make(map[string]string)
+	for _, user := range opt.Users {
+		usernames[user.ID] = user.Username
+	}
+	report := &LoadReport{
+		TimeZone: loc.String(),
+		Start:    opt.Window.Start,
+		End:      opt.Window.End,
+		Users:    []*UserLoad{},
+	}
+	if calculator, ok := source.(*OnCallCalculator); ok {
+		report.ScheduleId = calculator.schedule.ID
+	}
+	for _, l := range loads {
+		l.load.Username = usernames[l.load.User]
+		l.load.Hours = l.total.Hours()
+		l.load.WeekendHours = l.weekend.Hours()
+		l.load.NightHours = l.night.Hours()
+		l.load.HolidayHours = l.holiday.Hours()
+		report.Users = append(report.Users, l.load)
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		a, b := report.Users[i], report.Users[j]
+		if a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+		return a.User < b.User
+	})
+	return report, nil
+}
+
+// LoadReport gets the schedule with its on-call shifts, or its iCal feed for ical schedules,
+// and reports its on-call load, see NewLoadReport
+func (service *ScheduleService) LoadReport(scheduleID string, opt *LoadReportOptions, options ...RequestOption) (*LoadReport, error) {
+	if opt == nil {
+		return nil, fmt.Errorf("load report options required")
+	}
+	if err := opt.Window.validate(); err != nil {
+		return nil, err
+	}
+	report := *opt
+	if report.Users == nil {
+		users, err := service.client.Users.ListAllUsers(&ListUserOptions{}, options...)
+		if err != nil {
+			return nil, err
+		}
+		report.Users = users
+	}
+	if report.AlertGroupsFilter != nil {
+		filter := *report.AlertGroupsFilter
+		if filter.StartedAt == "" {
+			filter.StartedAt = AlertGroupTimeRange(opt.Window.Start, opt.Window.End)
+		}
+		alertGroups, err := service.client.AlertGroups.ListAllAlertGroups(&filter, options...)
+		if err != nil {
+			return nil, err
+		}
+		report.AlertGroups = append(append([]*AlertGroup{}, opt.AlertGroups...), alertGroups...)
+	}
+
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	source, err := service.onCallSource(schedule, report.Users, options)
+	if err != nil {
+		return nil, err
+	}
+	result, err := NewLoadReport(source, &report)
+	if err != nil {
+		return nil, err
+	}
+	result.ScheduleId = schedule.ID
+	return result, nil
+}
+
+// WriteJSON writes the report as JSON document
+func (report *LoadReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSV writes the report as CSV with header, one row per user with hours rounded to hundredths
+func (report *LoadReport) WriteCSV(w io.Writer) error {
+	hours := func(h float64) string {
+		return strconv.FormatFloat(h, 'f', 2, 64)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user", "username", "periods", "hours", "weekend_hours", "night_hours", "holiday_hours", "incidents", "resolved_incidents"}); err != nil {
+		return err
+	}
+	for _, load := range report.Users {
+		err := cw.Write([]string{
+			load.User,
+			load.Username,
+			strconv.Itoa(load.Periods),
+			hours(load.Hours),
+			hours(load.WeekendHours),
+			hours(load.NightHours),
+			hours(load.HolidayHours),
+			strconv.Itoa(load.Incidents),
+			strconv.Itoa(load.ResolvedIncidents),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// splitByDayTime calls fn for parts of [start, end) split at local midnights, night start and night end
+func splitByDayTime(start, end time.Time, loc *time.Location, nightStart, nightEnd time.Duration, fn func(start, end time.Time)) {
+	var cuts []time.Time
+	first := civilDate(start.In(loc))
+	last := civilDate(end.In(loc))
+	for day := first; !day.After(last); day = day.Add(24 * time.Hour) {
+		for _, offset := range []time.Duration{0, nightStart, nightEnd} {
+			cut := wallTime(day, offset, loc)
+			if cut.After(start) && cut.Before(end) {
+				cuts = append(cuts, cut)
+			}
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Before(cuts[j]) })
+
+	from := start
+	for _, cut := range append(cuts, end) {
+		if cut.After(from) {
+			fn(from, cut)
+			from = cut
+		}
+	}
+}
+
+// wallTime returns time of day on the civil date in loc
+func wallTime(day time.Time, offset time.Duration, loc *time.Location) time.Time {
+	seconds := int(offset / time.Second)
+	return time.Date(day.Year(), day.Month(), day.Day(), seconds/3600, seconds%3600/60, seconds%60, 0, loc)
+}
+
+func inNight(local time.Time, nightStart, nightEnd time.Duration) bool {
+	hour, min, sec := local.Clock()
+	t := time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second
+	if nightStart <= nightEnd {
+		return t >= nightStart && t < nightEnd
+	}
+	return t >= nightStart || t < nightEnd
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, wd := range weekdays {
+		if wd == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package amixr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testLoadCalculator(t *testing.T) *OnCallCalculator {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU"}, []*OnCallShift{
+		testCalculatorShift("weekend", OnCallShiftTypeSingleEvent, "2020-09-11T18:00:00", 48*3600, 0, "A"),
+		testCalculatorShift("monday", OnCallShiftTypeSingleEvent, "2020-09-14T09:00:00", 8*3600, 0, "B"),
+		testCalculatorShift("pair", OnCallShiftTypeSingleEvent, "2020-09-14T12:00:00", 2*3600, 0, "C"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return calculator
+}
+
+func TestNewLoadReport(t *testing.T) {
+	resolvedBy := "A"
+	resolvedAt := "2020-09-12T04:00:00Z"
+	otherResolvedBy := "UOTHER"
+	otherResolvedAt := "2020-09-14T13:00:00Z"
+
+	report, err := NewLoadReport(testLoadCalculator(t), &LoadReportOptions{
+		Window:   Window{Start: time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 15, 0, 0, 0, 0, time.UTC)},
+		Holidays: []time.Time{time.Date(2020, 9, 14, 0, 0, 0, 0, time.UTC)},
+		Users:    []*User{{ID: "A", Username: "alice"}},
+		AlertGroups: []*AlertGroup{
+			{ID: "I1", CreatedAt: "2020-09-12T03:00:00Z", ResolvedBy: &resolvedBy, ResolvedAt: &resolvedAt},
+			{ID: "I2", CreatedAt: "2020-09-14T12:30:00.123456Z", ResolvedBy: &otherResolvedBy, ResolvedAt: &otherResolvedAt},
+			{ID: "I3", CreatedAt: "2020-09-20T12:00:00Z"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*UserLoad{
+		{User: "A", Username: "alice", Periods: 1, Hours: 48, WeekendHours: 42, NightHours: 18, Incidents: 1, ResolvedIncidents: 1},
+		{User: "B", Periods: 1, Hours: 8, HolidayHours: 8, Incidents: 1},
+		{User: "C", Periods: 1, Hours: 2, HolidayHours: 2, Incidents: 1},
+		{User: "UOTHER", ResolvedIncidents: 1},
+	}
+	if !reflect.DeepEqual(want, report.Users) {
+		for _, load := range report.Users {
+			t.Logf("%+v", load)
+		}
+		t.Errorf("unexpected user loads")
+	}
+	if report.ScheduleId != "SBM7DV7BKFUYU" || report.TimeZone != "UTC" {
+		t.Errorf("schedule id %q and time zone %q", report.ScheduleId, report.TimeZone)
+	}
+
+	var csvBuf bytes.Buffer
+	if err := report.WriteCSV(&csvBuf); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := strings.Join([]string{
+		"user,username,periods,hours,weekend_hours,night_hours,holiday_hours,incidents,resolved_incidents",
+		"A,alice,1,48.00,42.00,18.00,0.00,1,1",
+		"B,,1,8.00,0.00,0.00,8.00,1,0",
+		"C,,1,2.00,0.00,0.00,2.00,1,0",
+		"UOTHER,,0,0.00,0.00,0.00,0.00,0,1",
+		"",
+	}, "\n")
+	if got := csvBuf.String(); got != wantCSV {
+		t.Errorf("CSV is\n%s\nwant\n%s", got, wantCSV)
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := report.WriteJSON(&jsonBuf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded LoadReport
+	if err := json.Unmarshal(jsonBuf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Users, decoded.Users) || !decoded.Start.Equal(report.Start) {
+		t.Errorf("JSON does not round trip\n%s", jsonBuf.String())
+	}
+}
+
+func TestNewLoadReportTimeZone(t *testing.T) {
+	calculator, err := NewOnCallCalculator(&Schedule{ID: "SBM7DV7BKFUYU", TimeZone: "Europe/Berlin"}, []*OnCallShift{
+		testCalculatorShift("dst", OnCallShiftTypeSingleEvent, "2020-10-24T22:00:00", 12*3600, 0, "A"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := Window{Start: time.Date(2020, 10, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 10, 26, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name string
+		opt  *LoadReportOptions
+		want UserLoad
+	}{
+		{
+			name: "defaults",
+			opt:  &LoadReportOptions{Window: window},
+			want: UserLoad{User: "A", Periods: 1, Hours: 12, WeekendHours: 12, NightHours: 10},
+		},
+		{
+			name: "custom night and weekend",
+			opt:  &LoadReportOptions{Window: window, NightStart: 0, NightEnd: 6 * time.Hour, Weekend: []time.Weekday{time.Sunday}},
+			want: UserLoad{User: "A", Periods: 1, Hours: 12, WeekendHours: 10, NightHours: 7},
+		},
+		{
+			name: "holiday in schedule time zone",
+			opt:  &LoadReportOptions{Window: window, Holidays: []time.Time{time.Date(2020, 10, 24, 0, 0, 0, 0, time.UTC)}},
+			want: UserLoad{User: "A", Periods: 1, Hours: 12, WeekendHours: 12, NightHours: 10, HolidayHours: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewLoadReport(calculator, tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.TimeZone != "Europe/Berlin" {
+				t.Errorf("time zone is %s, want Europe/Berlin", report.TimeZone)
+			}
+			if len(report.Users) != 1 || !reflect.DeepEqual(tt.want, *report.Users[0]) {
+				t.Errorf("loads are %+v, want %+v", report.Users, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewLoadReport(calculator, &LoadReportOptions{Window: window, NightStart: 25 * time.Hour}); err == nil {
+		t.Error("expected error for night start out of day")
+	}
+	if _, err := NewLoadReport(calculator, nil); err == nil {
+		t.Error("expected error without options")
+	}
+}
+
+func TestScheduleLoadReport(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id": "SBM7DV7BKFUYU", "type": "calendar", "name": "Primary", "time_zone": "UTC", "on_call_now": []}`)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [{
+			"id": "OH3V5FYQEYJ6M",
+			"schedule_id": "SBM7DV7BKFUYU",
+			"type": "recurrent_event",
+			"name": "Days",
+			"level": 0,
+			"start": "2020-09-04T09:00:00",
+			"duration": 28800,
+			"frequency": "daily",
+			"interval": 1,
+			"users": ["U4DNY931HHJS5"]
+		}]}`)
+	})
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserBody)
+	})
+	mux.HandleFunc("/api/v1/alert_groups/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		q := r.URL.Query()
+		if q.Get("integration_id") != "CFRPV98RPR1U8" || q.Get("started_at") != "2020-09-10T00:00:00_2020-09-12T00:00:00" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"count": 1, "next": null, "previous": null, "results": [
+			{"id": "I68T24C13IFW1", "integration_id": "CFRPV98RPR1U8", "state": "new", "created_at": "2020-09-11T10:00:00Z"}
+		]}`)
+	})
+
+	report, err := client.Schedules.LoadReport("SBM7DV7BKFUYU", &LoadReportOptions{
+		Window:            Window{Start: time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 9, 12, 0, 0, 0, 0, time.UTC)},
+		AlertGroupsFilter: &ListAlertGroupOptions{IntegrationId: "CFRPV98RPR1U8"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*UserLoad{{User: "U4DNY931HHJS5", Username: "alex", Periods: 2, Hours: 16, Incidents: 1}}
+	if !reflect.DeepEqual(want, report.Users) {
+		t.Errorf("loads are %+v, want %+v", report.Users[0], want[0])
+	}
+}
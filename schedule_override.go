@@ -0,0 +1,142 @@
+package amixr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrOverrideOverlap is returned by AddOverride if requested time is already overridden
+var ErrOverrideOverlap = errors.New("amixr: override overlaps existing override")
+
+// Override is an override on-call shift with times resolved in the schedule time zone
+type Override struct {
+	Shift *OnCallShift
+	Users []string
+	Start time.Time
+	End   time.Time
+}
+
+// ListOverrides gets override shifts of the schedule which intersect the window, ordered by start
+func (service *ScheduleService) ListOverrides(scheduleID string, window Window, options ...RequestOption) ([]*Override, error) {
+	if err := window.validate(); err != nil {
+		return nil, err
+	}
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+	return service.listOverrides(schedule.ID, loc, window, options)
+}
+
+func (service *ScheduleService) listOverrides(scheduleID string, loc *time.Location, window Window, options []RequestOption) ([]*Override, error) {
+	shifts, err := service.client.OnCallShifts.ListAllOnCallShifts(&ListOnCallShiftOptions{ScheduleId: scheduleID}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []*Override
+	for _, shift := range shifts {
+		if shift.Type != OnCallShiftTypeOverride {
+			continue
+		}
+		start, err := shift.StartTime(loc)
+		if err != nil {
+			return nil, err
+		}
+		end := start.Add(time.Duration(shift.Duration) * time.Second)
+		if !window.Overlaps(start, end) {
+			continue
+		}
+		override := &Override{Shift: shift, Start: start, End: end}
+		if shift.Users != nil {
+			override.Users = *shift.Users
+		}
+		overrides = append(overrides, override)
+	}
+
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrides[i].Start.Before(overrides[j].Start)
+	})
+	return overrides, nil
+}
+
+// AddOverride puts user on call in the schedule from start till end, replacing regular shifts.
+// Times are truncated to seconds and converted to the schedule time zone; start which is
+// ambiguous as wall time there (repeated hour at DST end) is rejected.
+// Returned error matches ErrOverrideOverlap if the time is already overridden.
+func (service *ScheduleService) AddOverride(scheduleID string, user string, start, end time.Time, options ...RequestOption) (*OnCallShift, error) {
+	if user == "" {
+		return nil, fmt.Errorf("user required")
+	}
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	window := Window{Start: start, End: end}
+	if err := window.validate(); err != nil {
+		return nil, err
+	}
+
+	schedule, _, err := service.GetSchedule(scheduleID, &GetScheduleOptions{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	localStart := start.In(loc)
+	startStr := localStart.Format(onCallShiftTimeLayout)
+	if ambiguousWallTime(localStart) {
+		return nil, fmt.Errorf("override start %s is ambiguous in schedule time zone %s", startStr, loc)
+	}
+
+	existing, err := service.listOverrides(schedule.ID, loc, window, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		var ids []string
+		for _, override := range existing {
+			ids = append(ids, override.Shift.ID)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrOverrideOverlap, strings.Join(ids, ", "))
+	}
+
+	users := []string{user}
+	shift, _, err := service.client.OnCallShifts.CreateOnCallShift(&CreateOnCallShiftOptions{
+		ScheduleId: schedule.ID,
+		Type:       OnCallShiftTypeOverride,
+		Name:       fmt.Sprintf("Override %s %s", user, startStr),
+		Start:      startStr,
+		Duration:   int(end.Sub(start) / time.Second),
+		Users:      &users,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// ambiguousWallTime reports whether wall time of t occurs twice in its location,
+// so it can't be stored as OnCallShift.Start
+func ambiguousWallTime(t time.Time) bool {
+	_, offset := t.Zone()
+	wall := t.Format(onCallShiftTimeLayout)
+	for _, neighbour := range []time.Time{t.Add(-24 * time.Hour), t.Add(24 * time.Hour)} {
+		_, other := neighbour.Zone()
+		if other == offset {
+			continue
+		}
+		alternative := t.Add(time.Duration(offset-other) * time.Second)
+		if alternative.Format(onCallShiftTimeLayout) == wall {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package amixr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testOverrideScheduleBody = `{
+	"id": "SBM7DV7BKFUYU",
+	"type": "calendar",
+	"name": "Test Schedule",
+	"time_zone": "Europe/Berlin",
+	"ical_url": null,
+	"slack": null,
+	"on_call_now": []
+}`
+
+var testOverrideShiftsBody = `{"count": 3, "next": null, "previous": null, "results": [
+	{
+		"id": "O1",
+		"schedule_id": "SBM7DV7BKFUYU",
+		"type": "override",
+		"name": "Override",
+		"level": 0,
+		"start": "2020-09-10T09:00:00",
+		"duration": 28800,
+		"users": ["U4DNY931HHJS5"]
+	},
+	{
+		"id": "O2",
+		"schedule_id": "SBM7DV7BKFUYU",
+		"type": "override",
+		"name": "Override",
+		"level": 0,
+		"start": "2020-09-20T09:00:00",
+		"duration": 3600,
+		"users": ["U6RV9WPSL6DFW"]
+	},
+	%s
+]}`
+
+func setupOverrides(t *testing.T, created func(*CreateOnCallShiftOptions)) (*http.ServeMux, func(), *Client) {
+	mux, server, client := setup(t)
+
+	mux.HandleFunc("/api/v1/schedules/SBM7DV7BKFUYU/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testOverrideScheduleBody)
+	})
+	mux.HandleFunc("/api/v1/on_call_shifts/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if got := r.URL.Query().Get("schedule_id"); got != "SBM7DV7BKFUYU" {
+				t.Errorf("schedule_id is %q, want %q", got, "SBM7DV7BKFUYU")
+			}
+			fmt.Fprintf(w, testOverrideShiftsBody, testOnCallShiftBody)
+		case "POST":
+			var opt CreateOnCallShiftOptions
+			if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+				t.Fatal(err)
+			}
+			if created != nil {
+				created(&opt)
+			}
+			fmt.Fprintf(w, `{"id": "O3", "schedule_id": "SBM7DV7BKFUYU", "type": "override", "name": %q, "level": 0, "start": %q, "duration": %d, "users": ["U4DNY931HHJS5"]}`, opt.Name, opt.Start, opt.Duration)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	return mux, func() { teardown(server) }, client
+}
+
+func TestListOverrides(t *testing.T) {
+	_, teardown, client := setupOverrides(t, nil)
+	defer teardown()
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	window := Window{
+		Start: time.Date(2020, 9, 10, 12, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 9, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	overrides, err := client.Schedules.ListOverrides("SBM7DV7BKFUYU", window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 2 {
+		t.Fatalf("returned %d overrides, want 2", len(overrides))
+	}
+	if !overrides[0].Start.Equal(time.Date(2020, 9, 10, 9, 0, 0, 0, berlin)) || !overrides[0].End.Equal(time.Date(2020, 9, 10, 17, 0, 0, 0, berlin)) {
+		t.Errorf("unexpected first override interval %s - %s", overrides[0].Start, overrides[0].End)
+	}
+	if overrides[1].Shift.ID != "O2" || overrides[1].Users[0] != "U6RV9WPSL6DFW" {
+		t.Errorf("unexpected second override %+v", overrides[1])
+	}
+
+	if _, err := client.Schedules.ListOverrides("SBM7DV7BKFUYU", Window{Start: window.End, End: window.Start}); err == nil {
+		t.Error("expected error for inverted window")
+	}
+}
+
+func TestAddOverride(t *testing.T) {
+	var created *CreateOnCallShiftOptions
+	_, teardown, client := setupOverrides(t, func(opt *CreateOnCallShiftOptions) { created = opt })
+	defer teardown()
+
+	start := time.Date(2020, 9, 12, 6, 0, 0, 0, time.UTC)
+	shift, err := client.Schedules.AddOverride("SBM7DV7BKFUYU", "U4DNY931HHJS5", start, start.Add(12*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shift.ID != "O3" {
+		t.Errorf("returned shift %s, want O3", shift.ID)
+	}
+
+	if created == nil {
+		t.Fatal("override was not created")
+	}
+	if created.Type != OnCallShiftTypeOverride || created.ScheduleId != "SBM7DV7BKFUYU" {
+		t.Errorf("unexpected create options %+v", created)
+	}
+	// 06:00 UTC is 08:00 in Berlin summer time
+	if created.Start != "2020-09-12T08:00:00" || created.Duration != 43200 {
+		t.Errorf("start is %s for %d seconds, want 2020-09-12T08:00:00 for 43200", created.Start, created.Duration)
+	}
+	if created.Users == nil || len(*created.Users) != 1 || (*created.Users)[0] != "U4DNY931HHJS5" {
+		t.Errorf("unexpected users %v", created.Users)
+	}
+}
+
+func TestAddOverrideOverlap(t *testing.T) {
+	_, teardown, client := setupOverrides(t, func(opt *CreateOnCallShiftOptions) {
+		t.Error("overlapping override must not be created")
+	})
+	defer teardown()
+
+	start := time.Date(2020, 9, 10, 14, 0, 0, 0, time.UTC)
+	_, err := client.Schedules.AddOverride("SBM7DV7BKFUYU", "U6RV9WPSL6DFW", start, start.Add(time.Hour))
+	if !errors.Is(err, ErrOverrideOverlap) {
+		t.Errorf("expected ErrOverrideOverlap, got %v", err)
+	}
+}
+
+func TestAddOverrideValidation(t *testing.T) {
+	_, teardown, client := setupOverrides(t, func(opt *CreateOnCallShiftOptions) {
+		t.Errorf("invalid override must not be created: %+v", opt)
+	})
+	defer teardown()
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	start := time.Date(2020, 9, 12, 6, 0, 0, 0, time.UTC)
+	// 02:30 on 25 October 2020 happens twice in Berlin, at 00:30 and 01:30 UTC
+	repeated := time.Date(2020, 10, 25, 0, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		user       string
+		start, end time.Time
+	}{
+		{"empty user", "", start, start.Add(time.Hour)},
+		{"end before start", "U4DNY931HHJS5", start, start.Add(-time.Hour)},
+		{"zero start", "U4DNY931HHJS5", time.Time{}, start},
+		{"repeated wall time first pass", "U4DNY931HHJS5", repeated.In(berlin), repeated.Add(3 * time.Hour)},
+		{"repeated wall time second pass", "U4DNY931HHJS5", repeated.Add(time.Hour), repeated.Add(3 * time.Hour)},
+	}
+	for _, tt := range tests {
+		if _, err := client.Schedules.AddOverride("SBM7DV7BKFUYU", tt.user, tt.start, tt.end); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -34,10 +34,10 @@ type ListSlackChannelOptions struct {
 }
 
 // ListSlackChannels gets all slackChannels for authorized team
-func (service *SlackChannelService) ListSlackChannels(opt *ListSlackChannelOptions) (*PaginatedSlackChannelsResponse, *http.Response, error) {
+func (service *SlackChannelService) ListSlackChannels(opt *ListSlackChannelOptions, options ...RequestOption) (*PaginatedSlackChannelsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -50,3 +50,44 @@ func (service *SlackChannelService) ListSlackChannels(opt *ListSlackChannelOptio
 
 	return slackChannels, resp, err
 }
+
+// SlackChannelIterator iterates over slack channels of all pages.
+// Use SlackChannelService.Iter to create one.
+type SlackChannelIterator struct {
+	pager
+	items []*SlackChannel
+}
+
+// Iter returns iterator over all slack channels matching opt, following pagination links
+func (service *SlackChannelService) Iter(opt *ListSlackChannelOptions, options ...RequestOption) *SlackChannelIterator {
+	u := fmt.Sprintf("%s", service.url)
+	it := &SlackChannelIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedSlackChannelsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.SlackChannels
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current slackChannel
+func (it *SlackChannelIterator) Value() *SlackChannel {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllSlackChannels gets slack channels of all pages
+func (service *SlackChannelService) ListAllSlackChannels(opt *ListSlackChannelOptions, options ...RequestOption) ([]*SlackChannel, error) {
+	var slackChannels []*SlackChannel
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		slackChannels = append(slackChannels, it.Value())
+	}
+	return slackChannels, it.Err()
+}
@@ -28,25 +28,36 @@ type PaginatedUsersResponse struct {
 }
 
 type User struct {
-	ID     string `json:"id"`
+	ID                    string       `json:"id"`
+	TeamId                string       `json:"team_id"`
+	Name                  string       `json:"name"`
+	Username              string       `json:"username"`
+	Role                  string       `json:"role"`
+	Email                 string       `json:"email"`
+	PhoneNumber           *string      `json:"phone_number"`
+	IsPhoneNumberVerified bool         `json:"is_phone_number_verified"`
+	Timezone              *string      `json:"timezone"`
+	Slack                 []*SlackUser `json:"slack"`
+}
+
+type SlackUser struct {
+	UserId string `json:"user_id"`
 	TeamId string `json:"team_id"`
-	Name   string `json:"name"`
-	Role   string `json:"role"`
-	Email  string `json:"email"`
 }
 
 type ListUserOptions struct {
 	ListOptions
-	Email string `url:"email,omitempty" json:"email,omitempty"`
+	Email    string `url:"email,omitempty" json:"email,omitempty"`
+	Username string `url:"username,omitempty" json:"username,omitempty"`
 }
 
 // ListUsers gets all users for authorized team
 //
 // http://api-docs.amixr.io/#list-users
-func (service *UserService) ListUsers(opt *ListUserOptions) (*PaginatedUsersResponse, *http.Response, error) {
+func (service *UserService) ListUsers(opt *ListUserOptions, options ...RequestOption) (*PaginatedUsersResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s/", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -60,16 +71,75 @@ func (service *UserService) ListUsers(opt *ListUserOptions) (*PaginatedUsersResp
 	return users, resp, err
 }
 
+// UserIterator iterates over users of all pages.
+// Use UserService.Iter to create one.
+type UserIterator struct {
+	pager
+	items []*User
+}
+
+// Iter returns iterator over all users matching opt, following pagination links
+func (service *UserService) Iter(opt *ListUserOptions, options ...RequestOption) *UserIterator {
+	u := fmt.Sprintf("%s/", service.url)
+	it := &UserIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedUsersResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.Users
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current user
+func (it *UserIterator) Value() *User {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllUsers gets users of all pages
+func (service *UserService) ListAllUsers(opt *ListUserOptions, options ...RequestOption) ([]*User, error) {
+	var users []*User
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		users = append(users, it.Value())
+	}
+	return users, it.Err()
+}
+
 type GetUserOptions struct {
 }
 
 // Get user by given id
 //
 // http://api-docs.amixr.io/#get-user
-func (service *UserService) GetUser(id string, opt *GetUserOptions) (*User, *http.Response, error) {
+func (service *UserService) GetUser(id string, opt *GetUserOptions, options ...RequestOption) (*User, *http.Response, error) {
 	u := fmt.Sprintf("%s/%s/", service.url, id)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := new(User)
+	resp, err := service.client.Do(req, user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return user, resp, err
+}
+
+// GetCurrentUser gets user the client token belongs to
+func (service *UserService) GetCurrentUser(options ...RequestOption) (*User, *http.Response, error) {
+	u := fmt.Sprintf("%s/current/", service.url)
+
+	req, err := service.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
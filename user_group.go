@@ -3,8 +3,12 @@ package amixr
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// Handles requests to user group endpoint
+// User groups are synced from Slack, so they can't be created, updated or deleted through API.
+// Use NewUserGroupService instead of direct creation UserGroupService
 type UserGroupService struct {
 	client *Client
 	url    string
@@ -41,10 +45,10 @@ type ListUserGroupOptions struct {
 }
 
 // ListUserGroups gets all UserGroups for authorized team
-func (service *UserGroupService) ListUserGroups(opt *ListUserGroupOptions) (*PaginatedUserGroupsResponse, *http.Response, error) {
+func (service *UserGroupService) ListUserGroups(opt *ListUserGroupOptions, options ...RequestOption) (*PaginatedUserGroupsResponse, *http.Response, error) {
 	u := fmt.Sprintf("%s", service.url)
 
-	req, err := service.client.NewRequest("GET", u, opt)
+	req, err := service.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,3 +61,116 @@ func (service *UserGroupService) ListUserGroups(opt *ListUserGroupOptions) (*Pag
 
 	return userGroups, resp, err
 }
+
+// UserGroupIterator iterates over user groups of all pages.
+// Use UserGroupService.Iter to create one.
+type UserGroupIterator struct {
+	pager
+	items []*UserGroup
+}
+
+// Iter returns iterator over all user groups matching opt, following pagination links
+func (service *UserGroupService) Iter(opt *ListUserGroupOptions, options ...RequestOption) *UserGroupIterator {
+	u := fmt.Sprintf("%s", service.url)
+	it := &UserGroupIterator{}
+	it.pager = newPager(service.client, u, opt, options, func(pages *pageIterator) (int, bool) {
+		page := new(PaginatedUserGroupsResponse)
+		if !pages.next(page) {
+			it.items = nil
+			return 0, false
+		}
+		it.items = page.UserGroups
+		return len(it.items), true
+	})
+	return it
+}
+
+// Value returns current userGroup
+func (it *UserGroupIterator) Value() *UserGroup {
+	if i := it.current(); i >= 0 {
+		return it.items[i]
+	}
+	return nil
+}
+
+// ListAllUserGroups gets user groups of all pages
+func (service *UserGroupService) ListAllUserGroups(opt *ListUserGroupOptions, options ...RequestOption) ([]*UserGroup, error) {
+	var userGroups []*UserGroup
+	it := service.Iter(opt, options...)
+	for it.Next() {
+		userGroups = append(userGroups, it.Value())
+	}
+	return userGroups, it.Err()
+}
+
+type GetUserGroupOptions struct {
+}
+
+// Get user group by given id
+func (service *UserGroupService) GetUserGroup(id string, opt *GetUserGroupOptions, options ...RequestOption) (*UserGroup, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userGroup := new(UserGroup)
+	resp, err := service.client.Do(req, userGroup)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return userGroup, resp, err
+}
+
+type ListUserGroupMemberOptions struct {
+	ListOptions
+}
+
+// ListUserGroupMembers gets users which are members of user group with given id
+func (service *UserGroupService) ListUserGroupMembers(id string, opt *ListUserGroupMemberOptions, options ...RequestOption) (*PaginatedUsersResponse, *http.Response, error) {
+	u := fmt.Sprintf("%s/%s/members/", service.url, id)
+
+	req, err := service.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var users *PaginatedUsersResponse
+	resp, err := service.client.Do(req, &users)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return users, resp, err
+}
+
+// ResolveSlackHandle returns id of user group with given Slack handle, e.g. to fill Escalation.GroupToNotify.
+// Leading "@" is ignored. Returned error matches ErrNotFound if there is no such group.
+func (service *UserGroupService) ResolveSlackHandle(handle string, options ...RequestOption) (string, error) {
+	handle = strings.TrimPrefix(handle, "@")
+	if handle == "" {
+		return "", fmt.Errorf("slack handle required")
+	}
+
+	userGroups, err := service.ListAllUserGroups(&ListUserGroupOptions{SlackHandle: handle}, options...)
+	if err != nil {
+		return "", err
+	}
+
+	var ids []string
+	for _, userGroup := range userGroups {
+		if userGroup.SlackUserGroup != nil && userGroup.SlackUserGroup.Handle == handle {
+			ids = append(ids, userGroup.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("user group with slack handle %q: %w", handle, ErrNotFound)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("slack handle %q matches several user groups: %s", handle, strings.Join(ids, ", "))
+	}
+}
@@ -1,6 +1,7 @@
 package amixr
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -59,3 +60,69 @@ func TestListUserGroups(t *testing.T) {
 		t.Errorf("returned\n %+v, \nwant\n %+v", userGroups, want)
 	}
 }
+
+func TestGetUserGroup(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/user_groups/GPFAPH7J7BKJB/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testUserGroupBody)
+	})
+
+	userGroup, _, err := client.UserGroups.GetUserGroup("GPFAPH7J7BKJB", &GetUserGroupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testUserGroup
+
+	if !reflect.DeepEqual(want, userGroup) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", userGroup, want)
+	}
+}
+
+func TestListUserGroupMembers(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/user_groups/GPFAPH7J7BKJB/members/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserBody))
+	})
+
+	users, _, err := client.UserGroups.ListUserGroupMembers("GPFAPH7J7BKJB", &ListUserGroupMemberOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users.Users) != 1 || users.Users[0].ID != testUser.ID {
+		t.Errorf("returned %+v, want single user %s", users.Users, testUser.ID)
+	}
+}
+
+func TestResolveSlackHandle(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/user_groups/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		if r.URL.Query().Get("slack_handle") == "test" {
+			fmt.Fprint(w, fmt.Sprintf(`{"count": 1, "next": null, "previous": null, "results": [%s]}`, testUserGroupBody))
+			return
+		}
+		fmt.Fprint(w, `{"count": 0, "next": null, "previous": null, "results": []}`)
+	})
+
+	id, err := client.UserGroups.ResolveSlackHandle("@test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "GPFAPH7J7BKJB" {
+		t.Errorf("resolved %q, want %q", id, "GPFAPH7J7BKJB")
+	}
+
+	if _, err := client.UserGroups.ResolveSlackHandle("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
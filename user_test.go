@@ -7,24 +7,41 @@ import (
 	"testing"
 )
 
+var testUserPhoneNumber = "+1234567890"
+var testUserTimezone = "Europe/Amsterdam"
+
 var testUser = &User{
-	ID:     "U4DNY931HHJS5",
-	TeamId: "TCNPY4A1BWUMP",
-	Email:  "public-api-demo-user-1@amixr.io",
-	Role:   "admin",
-	Name:   "Alex",
+	ID:                    "U4DNY931HHJS5",
+	TeamId:                "TCNPY4A1BWUMP",
+	Email:                 "public-api-demo-user-1@amixr.io",
+	Username:              "alex",
+	Role:                  "admin",
+	Name:                  "Alex",
+	PhoneNumber:           &testUserPhoneNumber,
+	IsPhoneNumberVerified: true,
+	Timezone:              &testUserTimezone,
+	Slack: []*SlackUser{
+		{
+			UserId: "UALEXSLACKDJPK",
+			TeamId: "TALEXSLACKDJPK",
+		},
+	},
 }
 
 var testUserBody = `{
 	"id": "U4DNY931HHJS5",
 	"team_id": "TCNPY4A1BWUMP",
 	"email": "public-api-demo-user-1@amixr.io",
+	"username": "alex",
 	"slack": [
 		{
 			"user_id": "UALEXSLACKDJPK",
 			"team_id": "TALEXSLACKDJPK"
 		}
 	],
+	"phone_number": "+1234567890",
+	"is_phone_number_verified": true,
+	"timezone": "Europe/Amsterdam",
 	"name": "Alex",
 	"role": "admin"
 }`
@@ -83,3 +100,25 @@ func TestGetUser(t *testing.T) {
 		t.Errorf("returned\n %+v\n want\n %+v\n", user, want)
 	}
 }
+
+func TestGetCurrentUser(t *testing.T) {
+	mux, server, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/api/v1/users/current/", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "GET")
+		fmt.Fprint(w, testUserBody)
+	})
+
+	user, _, err := client.Users.GetCurrentUser()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testUser
+
+	if !reflect.DeepEqual(want, user) {
+		t.Errorf("returned\n %+v\n want\n %+v\n", user, want)
+	}
+}